@@ -0,0 +1,205 @@
+package checklinks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"freectl/internal/output"
+	"freectl/internal/preprocessing"
+	"freectl/internal/settings"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sourceName  string
+	all         bool
+	redirects   bool
+	concurrency int
+	delay       time.Duration
+	timeout     time.Duration
+	maxAge      time.Duration
+)
+
+// CheckLinksCmd represents the check-links command
+var CheckLinksCmd = &cobra.Command{
+	Use:   "check-links",
+	Short: "Check the links of processed sources for dead ones",
+	Long: `Check whether the links of processed sources still work, and list the dead ones.
+
+Each link is requested with HEAD, or GET when a server doesn't answer HEAD. The
+status code, where it redirected to and when it was checked are stored on the
+processed items, so searches can demote or hide dead links (see the deadLinks
+setting and 'freectl search --dead-links').
+
+A link is dead when it can't be reached or answers 404, 410 or a server error.
+Several hosts are checked at once, but requests to the same host are made one
+at a time with --delay between them. Links checked within --max-age are skipped
+unless --all is given. Interrupting the check keeps the results so far.
+
+Processing can check links too, with 'freectl process --check-links' or the
+checkLinks setting.
+
+Examples:
+  # Check the links of every processed source
+  freectl check-links
+
+  # Check one source again from scratch, listing redirected links too
+  freectl check-links --source awesome-selfhosted --all --redirects
+
+  # List the dead links as JSON
+  freectl check-links --output json`,
+	Args: cobra.NoArgs,
+	RunE: runCheckLinks,
+}
+
+func init() {
+	defaults := preprocessing.DefaultLinkCheckConfig()
+	CheckLinksCmd.Flags().StringVarP(&sourceName, "source", "s", "", "Check the links of a specific source")
+	CheckLinksCmd.Flags().BoolVarP(&all, "all", "a", false, "Check every link, even recently checked ones")
+	CheckLinksCmd.Flags().BoolVar(&redirects, "redirects", false, "List redirected links as well as dead ones")
+	CheckLinksCmd.Flags().IntVarP(&concurrency, "concurrency", "c", defaults.Concurrency, "Number of hosts to check at once")
+	CheckLinksCmd.Flags().DurationVar(&delay, "delay", defaults.HostDelay, "Pause between requests to the same host")
+	CheckLinksCmd.Flags().DurationVar(&timeout, "timeout", defaults.Timeout, "Timeout for each link")
+	CheckLinksCmd.Flags().DurationVar(&maxAge, "max-age", defaults.MaxAge, "Check links again once their last check is this old")
+}
+
+// linkRecord is a dead or redirected link that check-links reports
+type linkRecord struct {
+	Source     string    `json:"source"`
+	Name       string    `json:"name"`
+	URL        string    `json:"url"`
+	Status     int       `json:"status,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Dead       bool      `json:"dead"`
+	FinalURL   string    `json:"final_url,omitempty"`
+	CheckedAt  time.Time `json:"checked_at"`
+	LineNumber int       `json:"line_number,omitempty"`
+	FilePath   string    `json:"file_path,omitempty"`
+}
+
+func runCheckLinks(cmd *cobra.Command, args []string) error {
+	format, err := output.FromFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	s, err := settings.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load settings: %w", err)
+	}
+
+	storage := preprocessing.NewFileStorage(filepath.Join(s.CacheDir, "processed"))
+	names, err := storage.List()
+	if err != nil {
+		return fmt.Errorf("failed to list processed sources: %w", err)
+	}
+	if sourceName != "" {
+		names = matchingSources(storage, names, sourceName)
+		if len(names) == 0 {
+			return fmt.Errorf("processed source '%s' not found", sourceName)
+		}
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("no processed sources found. Run 'freectl process' first")
+	}
+
+	engine := preprocessing.NewProcessingEngine(s.CacheDir, preprocessing.DefaultProcessingConfig())
+	engine.SetLinkChecker(preprocessing.NewLinkChecker(preprocessing.LinkCheckConfig{
+		Concurrency: concurrency,
+		HostDelay:   delay,
+		Timeout:     timeout,
+		MaxAge:      maxAge,
+	}))
+
+	var checked, dead atomic.Int64
+	progress := func(link string, health preprocessing.LinkHealth) {
+		checked.Add(1)
+		if health.Dead() {
+			dead.Add(1)
+			log.Debug("Dead link", "url", link, "status", health.StatusCode, "error", health.Error)
+		}
+	}
+
+	var records []linkRecord
+	for _, name := range names {
+		processed, err := engine.CheckLinks(cmd.Context(), name, all, progress)
+		if processed != nil {
+			records = append(records, reportedLinks(processed)...)
+		}
+		if err != nil && cmd.Context().Err() != nil {
+			log.Warn("Link check interrupted, kept the results so far")
+			break
+		}
+		if err != nil {
+			log.Error("Failed to check links", "name", name, "error", err)
+		}
+	}
+
+	log.Info("Link check completed", "checked", checked.Load(), "dead", dead.Load())
+
+	if len(records) == 0 && format == output.FormatTable {
+		log.Info("No dead links found")
+		return nil
+	}
+
+	columns := []string{"SOURCE", "STATUS", "URL", "DETAIL", "NAME"}
+	return output.Write(os.Stdout, format, columns, records, func(r linkRecord) []string {
+		status := "redirected"
+		if r.Dead {
+			status = "dead"
+		}
+		if r.Status != 0 {
+			status += " (" + strconv.Itoa(r.Status) + ")"
+		}
+		detail := r.Error
+		if r.FinalURL != "" {
+			detail = "→ " + r.FinalURL
+		}
+		return []string{r.Source, status, r.URL, detail, r.Name}
+	})
+}
+
+// reportedLinks returns the dead links of a processed source, and the
+// redirected ones with --redirects
+func reportedLinks(processed *preprocessing.ProcessedSource) []linkRecord {
+	var records []linkRecord
+	for _, item := range processed.Items {
+		health := item.Health
+		if !health.Dead() && !(redirects && health.Redirected()) {
+			continue
+		}
+		records = append(records, linkRecord{
+			Source:     processed.Source.Name,
+			Name:       item.Name,
+			URL:        item.URL,
+			Status:     health.StatusCode,
+			Error:      health.Error,
+			Dead:       health.Dead(),
+			FinalURL:   health.FinalURL,
+			CheckedAt:  health.CheckedAt,
+			LineNumber: item.Metadata.LineNumber,
+			FilePath:   item.Metadata.FilePath,
+		})
+	}
+	return records
+}
+
+// matchingSources returns the stored sources whose name is name, ignoring case
+func matchingSources(storage preprocessing.ProcessedStorage, names []string, name string) []string {
+	for _, stored := range names {
+		if strings.EqualFold(stored, name) {
+			return []string{stored}
+		}
+		if processed, err := storage.Load(stored); err == nil && strings.EqualFold(processed.Source.Name, name) {
+			return []string{stored}
+		}
+	}
+	return nil
+}
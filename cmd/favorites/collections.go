@@ -0,0 +1,145 @@
+package favorites
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"freectl/internal/output"
+	"freectl/internal/search"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+)
+
+// collectionsCmd groups the commands for managing favorite collections
+var collectionsCmd = &cobra.Command{
+	Use:     "collections",
+	Aliases: []string{"collection"},
+	Short:   "Manage named collections of favorites",
+	Long: `Manage named collections of favorites, such as a toolbox per project.
+A favorite can be in any number of collections.
+
+Examples:
+  freectl favorites collections create docs-toolbox --description "Docs tooling"
+  freectl favorites collections add docs-toolbox https://js.wiki
+  freectl favorites list --collection docs-toolbox`,
+}
+
+// collectionsListCmd represents the favorites collections list command
+var collectionsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List collections and how many favorites are in each",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		collections, err := search.ListCollections()
+		if err != nil {
+			return fmt.Errorf("failed to load collections: %w", err)
+		}
+
+		f, err := output.FromFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		if len(collections) == 0 && f == output.FormatTable {
+			fmt.Println("No collections yet. Create one using 'freectl favorites collections create'")
+			return nil
+		}
+
+		columns := []string{"NAME", "FAVORITES", "DESCRIPTION"}
+		return output.Write(os.Stdout, f, columns, collections, func(c search.CollectionSummary) []string {
+			return []string{c.Name, strconv.Itoa(c.Count), c.Description}
+		})
+	},
+}
+
+// collectionsCreateCmd represents the favorites collections create command
+var collectionsCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create an empty collection",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := search.CreateCollection(args[0], description); err != nil {
+			return fmt.Errorf("failed to create collection: %w", err)
+		}
+
+		log.Info("Created collection", "name", args[0])
+		return nil
+	},
+}
+
+// collectionsRenameCmd represents the favorites collections rename command
+var collectionsRenameCmd = &cobra.Command{
+	Use:   "rename <name> <new name>",
+	Short: "Rename a collection",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := search.RenameCollection(args[0], args[1]); err != nil {
+			return fmt.Errorf("failed to rename collection: %w", err)
+		}
+
+		log.Info("Renamed collection", "from", args[0], "to", args[1])
+		return nil
+	},
+}
+
+// collectionsDeleteCmd represents the favorites collections delete command
+var collectionsDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a collection, keeping its favorites",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := search.DeleteCollection(args[0]); err != nil {
+			return fmt.Errorf("failed to delete collection: %w", err)
+		}
+
+		log.Info("Deleted collection", "name", args[0])
+		return nil
+	},
+}
+
+// collectionsAddCmd represents the favorites collections add command
+var collectionsAddCmd = &cobra.Command{
+	Use:   "add <name> <url>...",
+	Short: "Put favorites in a collection",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, link := range args[1:] {
+			if err := search.AddToCollection(link, args[0]); err != nil {
+				return fmt.Errorf("failed to add to collection: %w", err)
+			}
+		}
+
+		log.Info("Added to collection", "name", args[0], "favorites", len(args)-1)
+		return nil
+	},
+}
+
+// collectionsRemoveCmd represents the favorites collections remove command
+var collectionsRemoveCmd = &cobra.Command{
+	Use:   "remove <name> <url>...",
+	Short: "Take favorites out of a collection",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, link := range args[1:] {
+			if err := search.RemoveFromCollection(link, args[0]); err != nil {
+				return fmt.Errorf("failed to remove from collection: %w", err)
+			}
+		}
+
+		log.Info("Removed from collection", "name", args[0], "favorites", len(args)-1)
+		return nil
+	},
+}
+
+func init() {
+	collectionsCreateCmd.Flags().StringVarP(&description, "description", "d", "", "Description of the collection")
+
+	collectionsCmd.AddCommand(collectionsListCmd)
+	collectionsCmd.AddCommand(collectionsCreateCmd)
+	collectionsCmd.AddCommand(collectionsRenameCmd)
+	collectionsCmd.AddCommand(collectionsDeleteCmd)
+	collectionsCmd.AddCommand(collectionsAddCmd)
+	collectionsCmd.AddCommand(collectionsRemoveCmd)
+}
@@ -0,0 +1,457 @@
+package favorites
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"freectl/internal/common"
+	"freectl/internal/output"
+	"freectl/internal/search"
+	"freectl/internal/settings"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	name        string
+	description string
+	category    string
+	notes       string
+	tags        []string
+	collections []string
+	collection  string
+	tag         string
+	format      string
+	awesome     search.AwesomeList
+)
+
+// FavoritesCmd groups the commands for managing favorites
+var FavoritesCmd = &cobra.Command{
+	Use:     "favorites",
+	Aliases: []string{"favourites", "fav"},
+	Short:   "Manage favorite resources",
+	Long: `Manage the resources saved as favorites from the search UIs.
+
+Favorites can be exported to and imported from Netscape bookmark files (as
+used by browsers), markdown lists and JSON.
+
+Examples:
+  # List favorites
+  freectl favorites list
+
+  # Add and remove a favorite
+  freectl favorites add https://js.wiki --name "Wiki.js" --category Wikis
+  freectl favorites remove https://js.wiki
+
+  # Group favorites into collections, with tags and notes
+  freectl favorites add https://js.wiki --collection docs-toolbox --tag wiki --note "Try the Git sync"
+  freectl favorites list --collection docs-toolbox
+  freectl favorites collections list
+
+  # Search favorites
+  freectl favorites search wiki
+
+  # Export favorites as browser bookmarks, then import them elsewhere
+  freectl favorites export bookmarks.html
+  freectl favorites import bookmarks.html`,
+}
+
+// listCmd represents the favorites list command
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List favorites",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		favorites, err := search.LoadFavorites()
+		if err != nil {
+			return fmt.Errorf("failed to load favorites: %w", err)
+		}
+		favorites = search.FilterFavorites(favorites, search.FavoriteFilter{Collection: collection, Tag: tag})
+		return writeFavorites(cmd, favorites, "No favorites found. Add one using 'freectl favorites add'")
+	},
+}
+
+// addCmd represents the favorites add command
+var addCmd = &cobra.Command{
+	Use:   "add <url>",
+	Short: "Add a favorite",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		link := args[0]
+		if common.ExtractURL(link) != link {
+			return fmt.Errorf("invalid URL: %s", link)
+		}
+
+		exists, err := search.IsFavorite(link)
+		if err != nil {
+			return fmt.Errorf("failed to load favorites: %w", err)
+		}
+		if exists {
+			return mergeFavorite(cmd, link)
+		}
+
+		favorite := search.Favorite{
+			Link:        link,
+			Name:        name,
+			Description: description,
+			Category:    category,
+			Notes:       notes,
+			Tags:        tags,
+			Collections: collections,
+		}
+		if favorite.Name == "" {
+			favorite.Name = common.ExtractDomain(link)
+		}
+
+		if err := search.AddFavorite(favorite); err != nil {
+			return fmt.Errorf("failed to add favorite: %w", err)
+		}
+
+		log.Info("Added favorite", "name", favorite.Name, "url", link)
+		return nil
+	},
+}
+
+// editCmd represents the favorites edit command
+var editCmd = &cobra.Command{
+	Use:   "edit <url>",
+	Short: "Edit a favorite",
+	Long: `Edit a favorite's details. Only the flags that are given are changed, and
+--tag and --collection replace the favorite's tags and collections.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		link := args[0]
+		flags := cmd.Flags()
+
+		err := search.UpdateFavorite(link, func(f *search.Favorite) {
+			if flags.Changed("name") {
+				f.Name = name
+			}
+			if flags.Changed("description") {
+				f.Description = description
+			}
+			if flags.Changed("category") {
+				f.Category = category
+			}
+			if flags.Changed("note") {
+				f.Notes = notes
+			}
+			if flags.Changed("tag") {
+				f.Tags = tags
+			}
+			if flags.Changed("collection") {
+				f.Collections = collections
+			}
+		})
+		if err != nil {
+			return fmt.Errorf("failed to edit favorite: %w", err)
+		}
+
+		log.Info("Updated favorite", "url", link)
+		return nil
+	},
+}
+
+// moveCmd represents the favorites move command
+var moveCmd = &cobra.Command{
+	Use:   "move <url> <position>",
+	Short: "Move a favorite to a position in the list, starting at 1",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		position, err := strconv.Atoi(args[1])
+		if err != nil || position < 1 {
+			return fmt.Errorf("invalid position '%s', expected a number from 1", args[1])
+		}
+
+		if err := search.MoveFavorite(args[0], position-1); err != nil {
+			return fmt.Errorf("failed to move favorite: %w", err)
+		}
+
+		log.Info("Moved favorite", "url", args[0], "position", position)
+		return nil
+	},
+}
+
+// removeCmd represents the favorites remove command
+var removeCmd = &cobra.Command{
+	Use:     "remove <url>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a favorite",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		link := args[0]
+		found, err := search.IsFavorite(link)
+		if err != nil {
+			return fmt.Errorf("failed to load favorites: %w", err)
+		}
+		if !found {
+			return fmt.Errorf("'%s' is not a favorite", link)
+		}
+
+		if err := search.RemoveFavorite(search.Favorite{Link: link}); err != nil {
+			return fmt.Errorf("failed to remove favorite: %w", err)
+		}
+
+		log.Info("Removed favorite", "url", link)
+		return nil
+	},
+}
+
+// searchCmd represents the favorites search command
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search favorites",
+	Long: `Search favorites with the same fuzzy matching as 'freectl search', over
+their names, descriptions, tags, collections and notes. The best matches are
+listed first.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := settings.LoadSettings()
+		if err != nil {
+			return fmt.Errorf("failed to load settings: %w", err)
+		}
+
+		favorites, err := search.LoadFavorites()
+		if err != nil {
+			return fmt.Errorf("failed to load favorites: %w", err)
+		}
+
+		searcher := search.NewFavoritesSearcher(s, search.FavoriteFilter{Collection: collection, Tag: tag})
+		resp, err := searcher.Search(cmd.Context(), search.Request{Query: args[0], PerPage: len(favorites)})
+		if err != nil {
+			return fmt.Errorf("failed to search favorites: %w", err)
+		}
+
+		// List the matching favorites themselves, best match first
+		byLink := make(map[string]search.Favorite, len(favorites))
+		for _, f := range favorites {
+			byLink[common.CanonicalURL(f.Link)] = f
+		}
+		matches := make([]search.Favorite, 0, len(resp.Results))
+		for _, r := range resp.Results {
+			matches = append(matches, byLink[common.CanonicalURL(r.URL)])
+		}
+
+		return writeFavorites(cmd, matches, "No favorites found")
+	},
+}
+
+// exportCmd represents the favorites export command
+var exportCmd = &cobra.Command{
+	Use:   "export [file]",
+	Short: "Export favorites as bookmarks, markdown, JSON or an awesome list",
+	Long: `Export favorites to a file, or to stdout when no file is given.
+
+The format is taken from the file extension (.html, .md or .json) unless
+--format is given. Stdout defaults to markdown.
+
+The awesome format generates an awesome list with a section per collection
+(or category, with --group-by category). Commit it to a git repository and
+teammates can add it as a source to search what you curated:
+
+  freectl favorites export awesome-toolbox.md --format awesome --title "Awesome Toolbox"
+  freectl add https://github.com/you/toolbox`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := ""
+		if len(args) == 1 {
+			path = args[0]
+		}
+
+		f, err := favoritesFormat(path, search.FavoritesMarkdown)
+		if err != nil {
+			return err
+		}
+
+		favorites, err := search.LoadFavorites()
+		if err != nil {
+			return fmt.Errorf("failed to load favorites: %w", err)
+		}
+		favorites = search.FilterFavorites(favorites, search.FavoriteFilter{Collection: collection, Tag: tag})
+
+		var w io.Writer = os.Stdout
+		if path != "" {
+			file, err := os.Create(path)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", path, err)
+			}
+			defer file.Close()
+			w = file
+		}
+
+		if f == search.FavoritesAwesome {
+			err = search.ExportAwesomeList(w, favorites, awesome)
+		} else {
+			err = search.ExportFavorites(w, favorites, f)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to export favorites: %w", err)
+		}
+
+		if path != "" {
+			log.Info("Exported favorites", "count", len(favorites), "file", path, "format", f)
+		}
+		return nil
+	},
+}
+
+// importCmd represents the favorites import command
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import favorites from bookmarks, markdown or JSON",
+	Long: `Import favorites from a file, skipping any that are already favorites.
+
+The format is taken from the file extension (.html, .md or .json) unless
+--format is given. Use '-' to read from stdin.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := args[0]
+
+		var r io.Reader = os.Stdin
+		if path != "-" {
+			file, err := os.Open(path)
+			if err != nil {
+				return fmt.Errorf("failed to open %s: %w", path, err)
+			}
+			defer file.Close()
+			r = file
+		}
+
+		f, err := favoritesFormat(path, search.FavoritesJSON)
+		if err != nil {
+			return err
+		}
+
+		imported, err := search.ImportFavorites(r, f)
+		if err != nil {
+			return fmt.Errorf("failed to import favorites: %w", err)
+		}
+
+		favorites, err := search.LoadFavorites()
+		if err != nil {
+			return fmt.Errorf("failed to load favorites: %w", err)
+		}
+
+		favorites, added := search.MergeFavorites(favorites, imported)
+		if err := search.SaveFavorites(favorites); err != nil {
+			return fmt.Errorf("failed to save favorites: %w", err)
+		}
+
+		log.Info("Imported favorites", "added", added, "skipped", len(imported)-added)
+		return nil
+	},
+}
+
+// favoritesFormat picks the file format from --format, the file extension or
+// the fallback when there is no file
+// mergeFavorite adds the flags given to favorites add to an existing
+// favorite: tags and collections are added to its own, the rest replace
+// its values
+func mergeFavorite(cmd *cobra.Command, link string) error {
+	flags := cmd.Flags()
+	changed := slices.ContainsFunc([]string{"name", "description", "category", "note", "tag", "collection"}, flags.Changed)
+	if !changed {
+		log.Info("Already a favorite", "url", link)
+		return nil
+	}
+
+	err := search.UpdateFavorite(link, func(f *search.Favorite) {
+		if flags.Changed("name") {
+			f.Name = name
+		}
+		if flags.Changed("description") {
+			f.Description = description
+		}
+		if flags.Changed("category") {
+			f.Category = category
+		}
+		if flags.Changed("note") {
+			f.Notes = notes
+		}
+		f.Tags = appendMissing(f.Tags, tags)
+		f.Collections = appendMissing(f.Collections, collections)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update favorite: %w", err)
+	}
+
+	log.Info("Updated existing favorite", "url", link)
+	return nil
+}
+
+// appendMissing appends the values not already in list, ignoring case
+func appendMissing(list, values []string) []string {
+	for _, value := range values {
+		if !slices.ContainsFunc(list, func(v string) bool { return strings.EqualFold(v, value) }) {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+func favoritesFormat(path string, fallback search.FavoritesFormat) (search.FavoritesFormat, error) {
+	if format != "" {
+		return search.ParseFavoritesFormat(format)
+	}
+	if path == "" || path == "-" {
+		return fallback, nil
+	}
+	return search.FavoritesFormatFromPath(path)
+}
+
+// writeFavorites prints favorites in the format chosen with --output
+func writeFavorites(cmd *cobra.Command, favorites []search.Favorite, empty string) error {
+	f, err := output.FromFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	if len(favorites) == 0 && f == output.FormatTable {
+		fmt.Println(empty)
+		return nil
+	}
+
+	columns := []string{"NAME", "URL", "CATEGORY", "COLLECTIONS", "TAGS", "DESCRIPTION"}
+	return output.Write(os.Stdout, f, columns, favorites, func(fav search.Favorite) []string {
+		return []string{fav.Name, fav.Link, fav.Category, strings.Join(fav.Collections, ", "), strings.Join(fav.Tags, ", "), fav.Description}
+	})
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{addCmd, editCmd} {
+		cmd.Flags().StringVarP(&name, "name", "n", "", "Name of the resource (default: the URL's domain)")
+		cmd.Flags().StringVarP(&description, "description", "d", "", "Description of the resource")
+		cmd.Flags().StringVarP(&category, "category", "c", "", "Category of the resource")
+		cmd.Flags().StringVar(&notes, "note", "", "Free-text notes about the resource")
+		cmd.Flags().StringSliceVarP(&tags, "tag", "t", nil, "Tag the favorite (repeatable)")
+		cmd.Flags().StringSliceVar(&collections, "collection", nil, "Add the favorite to a collection (repeatable)")
+	}
+
+	for _, cmd := range []*cobra.Command{listCmd, searchCmd, exportCmd} {
+		cmd.Flags().StringVar(&collection, "collection", "", "Only include favorites in this collection")
+		cmd.Flags().StringVarP(&tag, "tag", "t", "", "Only include favorites with this tag")
+	}
+
+	for _, cmd := range []*cobra.Command{exportCmd, importCmd} {
+		cmd.Flags().StringVarP(&format, "format", "f", "", "File format: html, markdown, json or awesome (default: from the file extension)")
+	}
+
+	exportCmd.Flags().StringVar(&awesome.Title, "title", "", "Title of the awesome list (default: Awesome Favorites)")
+	exportCmd.Flags().StringVar(&awesome.Description, "description", "", "Description of the awesome list")
+	exportCmd.Flags().StringVar(&awesome.GroupBy, "group-by", search.GroupByCollection, "Awesome list sections: collection or category")
+
+	FavoritesCmd.AddCommand(listCmd)
+	FavoritesCmd.AddCommand(addCmd)
+	FavoritesCmd.AddCommand(editCmd)
+	FavoritesCmd.AddCommand(moveCmd)
+	FavoritesCmd.AddCommand(removeCmd)
+	FavoritesCmd.AddCommand(collectionsCmd)
+	FavoritesCmd.AddCommand(searchCmd)
+	FavoritesCmd.AddCommand(exportCmd)
+	FavoritesCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,137 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"freectl/internal/output"
+	"freectl/internal/search"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+)
+
+var limit int
+
+// HistoryCmd represents the history command
+var HistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show recent searches",
+	Long: `Show recent searches with when they ran and how many results they found,
+most recent first.
+
+Queries can be saved under a name with 'freectl search --save <name>' and
+re-run with 'freectl search --saved <name>'.
+
+Examples:
+  # Show the last 20 searches
+  freectl history
+
+  # Show saved searches
+  freectl history saved
+
+  # Forget every past search
+  freectl history clear`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := output.FromFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		history, err := search.LoadHistory()
+		if err != nil {
+			return fmt.Errorf("failed to load history: %w", err)
+		}
+		if limit > 0 && len(history) > limit {
+			history = history[:limit]
+		}
+
+		if len(history) == 0 && format == output.FormatTable {
+			fmt.Println("No searches yet")
+			return nil
+		}
+
+		columns := []string{"SEARCHED", "QUERY", "SOURCES", "RESULTS"}
+		return output.Write(os.Stdout, format, columns, history, func(e search.HistoryEntry) []string {
+			return []string{formatTime(e.SearchedAt), e.Query, strings.Join(e.Filters.Sources, ", "), strconv.Itoa(e.Results)}
+		})
+	},
+}
+
+// clearCmd represents the history clear command
+var clearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Forget every past search",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := search.ClearHistory(); err != nil {
+			return fmt.Errorf("failed to clear history: %w", err)
+		}
+
+		log.Info("Cleared search history")
+		return nil
+	},
+}
+
+// savedCmd represents the history saved command
+var savedCmd = &cobra.Command{
+	Use:   "saved",
+	Short: "List saved searches",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := output.FromFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		saved, err := search.LoadSavedSearches()
+		if err != nil {
+			return fmt.Errorf("failed to load saved searches: %w", err)
+		}
+
+		if len(saved) == 0 && format == output.FormatTable {
+			fmt.Println("No saved searches. Save one using 'freectl search --save <name> <query>'")
+			return nil
+		}
+
+		columns := []string{"NAME", "QUERY", "SOURCES", "LAST RUN", "FOUND"}
+		return output.Write(os.Stdout, format, columns, saved, func(s search.SavedSearch) []string {
+			return []string{s.Name, s.Query, strings.Join(s.Filters.Sources, ", "), formatTime(s.LastRunAt), strconv.Itoa(len(s.Seen))}
+		})
+	},
+}
+
+// deleteSavedCmd represents the history saved delete command
+var deleteSavedCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a saved search",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := search.DeleteSavedSearch(args[0]); err != nil {
+			return fmt.Errorf("failed to delete saved search: %w", err)
+		}
+
+		log.Info("Deleted saved search", "name", args[0])
+		return nil
+	},
+}
+
+// formatTime formats a timestamp for tables, leaving unset ones blank
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+func init() {
+	HistoryCmd.Flags().IntVarP(&limit, "limit", "l", 20, "Maximum number of searches to show (0 for all)")
+
+	savedCmd.AddCommand(deleteSavedCmd)
+	HistoryCmd.AddCommand(clearCmd)
+	HistoryCmd.AddCommand(savedCmd)
+}
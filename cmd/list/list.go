@@ -2,8 +2,11 @@ package list
 
 import (
 	"fmt"
+	"os"
 
+	"freectl/internal/output"
 	"freectl/internal/settings"
+	sourcespkg "freectl/internal/sources"
 
 	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
@@ -21,8 +24,9 @@ var ListCmd = &cobra.Command{
 - Size on disk
 - Enabled status
 
-Example:
-  freectl list`,
+Examples:
+  freectl list
+  freectl list --output json`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		log.Debug("Starting list command")
 
@@ -34,94 +38,26 @@ Example:
 
 		log.Debug("Successfully retrieved sources", "count", len(sources))
 
-		if len(sources) == 0 {
-			fmt.Println("No sources found. Add one using 'freectl add'")
-			return nil
+		format, err := output.FromFlag(cmd)
+		if err != nil {
+			return err
 		}
 
-		// Calculate maximum widths
-		maxName := len("NAME")
-		maxURL := len("URL")
-		maxType := len("TYPE")
-		maxUpdate := len("LAST UPDATED")
-		maxSize := len("SIZE")
-		maxStatus := len("STATUS")
-
-		// Collect all data first to calculate widths
-		type sourceData struct {
-			name       string
-			url        string
-			sourceType string
-			lastUpdate string
-			size       string
-			status     string
+		if len(sources) == 0 && format == output.FormatTable {
+			fmt.Println("No sources found. Add one using 'freectl add'")
+			return nil
 		}
-		sourceDataList := make([]sourceData, 0, len(sources))
 
-		for _, source := range sources {
-			// Get status
+		columns := []string{"NAME", "URL", "TYPE", "LAST UPDATED", "SIZE", "STATUS"}
+		err = output.Write(os.Stdout, format, columns, sources, func(source sourcespkg.Source) []string {
 			status := "Enabled"
 			if !source.Enabled {
 				status = "Disabled"
 			}
-
-			// Store data and update max widths
-			data := sourceData{
-				name:       source.Name,
-				url:        source.URL,
-				sourceType: string(source.Type),
-				lastUpdate: source.LastUpdated,
-				size:       source.Size,
-				status:     status,
-			}
-			sourceDataList = append(sourceDataList, data)
-
-			if len(source.Name) > maxName {
-				maxName = len(source.Name)
-			}
-			if len(source.URL) > maxURL {
-				maxURL = len(source.URL)
-			}
-			if len(string(source.Type)) > maxType {
-				maxType = len(string(source.Type))
-			}
-			if len(source.LastUpdated) > maxUpdate {
-				maxUpdate = len(source.LastUpdated)
-			}
-			if len(source.Size) > maxSize {
-				maxSize = len(source.Size)
-			}
-			if len(status) > maxStatus {
-				maxStatus = len(status)
-			}
-		}
-
-		// Add padding
-		maxName += 3
-		maxURL += 3
-		maxType += 3
-		maxUpdate += 3
-		maxSize += 3
-		maxStatus += 3
-
-		// Print table header
-		fmt.Printf("%-*s %-*s %-*s %-*s %-*s %-*s\n",
-			maxName, "NAME",
-			maxURL, "URL",
-			maxType, "TYPE",
-			maxUpdate, "LAST UPDATED",
-			maxSize, "SIZE",
-			maxStatus, "STATUS")
-
-		// Print each source
-		for _, data := range sourceDataList {
-			fmt.Printf("%-*s %-*s %-*s %-*s %-*s %-*s\n",
-				maxName, data.name,
-				maxURL, data.url,
-				maxType, data.sourceType,
-				maxUpdate, data.lastUpdate,
-				maxSize, data.size,
-				maxStatus, data.status)
+			return []string{source.Name, source.URL, string(source.Type), source.LastUpdated, source.Size, status}
+		})
+		if err != nil {
+			return fmt.Errorf("failed to write sources: %w", err)
 		}
 
 		log.Debug("List command completed successfully")
@@ -0,0 +1,100 @@
+package open
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"freectl/internal/search"
+	"freectl/internal/settings"
+	"freectl/internal/tui"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sourceName string
+	printURL   bool
+	nth        int
+)
+
+// OpenCmd represents the open command
+var OpenCmd = &cobra.Command{
+	Use:     "open <query>",
+	Aliases: []string{"lucky"},
+	Short:   "Open the top search result in the browser",
+	Long: `Search through all cached sources and open the best match straight away,
+without showing the results first.
+
+This is handy from launchers such as rofi or Alfred-style scripts, where
+--print emits the URL instead of opening it.
+
+Examples:
+  # Open the best match for a query
+  freectl open "self-hosted wiki"
+
+  # Feeling lucky
+  freectl lucky torrent client
+
+  # Open the third best match
+  freectl open --nth 3 "kanban"
+
+  # Print the URL instead of opening it
+  freectl open --print "markdown editor" | xclip`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if nth < 1 {
+			return fmt.Errorf("--nth must be at least 1")
+		}
+
+		// Only the URL may go to stdout when printing it for scripts
+		if printURL {
+			log.SetOutput(os.Stderr)
+		}
+
+		s, err := settings.LoadSettings()
+		if err != nil {
+			return fmt.Errorf("failed to load settings: %w", err)
+		}
+
+		query := strings.Join(args, " ")
+		req := search.Request{Query: query, PerPage: nth}
+		if sourceName != "" {
+			req.Filters.Sources = []string{sourceName}
+		}
+
+		searcher := search.NewSearcher(s)
+		log.Debug("Searching", "query", query, "searcher", searcher.Name())
+		resp, err := searcher.Search(cmd.Context(), req)
+		if err != nil {
+			return fmt.Errorf("search failed: %w", err)
+		}
+
+		if len(resp.Results) < nth {
+			if len(resp.Results) == 0 {
+				return fmt.Errorf("no results found for '%s'", query)
+			}
+			return fmt.Errorf("only %d results found for '%s'", len(resp.Results), query)
+		}
+
+		result := resp.Results[nth-1]
+		if printURL {
+			fmt.Println(result.URL)
+			return nil
+		}
+
+		log.Info("Opening result", "name", result.Name, "url", result.URL, "source", result.Source)
+		if err := tui.OpenBrowser(result.URL); err != nil {
+			return fmt.Errorf("failed to open %s: %w", result.URL, err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	OpenCmd.Flags().StringVarP(&sourceName, "source", "r", "", "Search in a specific source")
+	OpenCmd.Flags().BoolVarP(&printURL, "print", "p", false, "Print the URL instead of opening it")
+	OpenCmd.Flags().IntVarP(&nth, "nth", "n", 1, "Open the result at this rank instead of the top one")
+}
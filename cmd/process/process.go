@@ -2,9 +2,14 @@ package process
 
 import (
 	"fmt"
+	"os"
+	"strconv"
 	"time"
 
+	"freectl/internal/output"
 	"freectl/internal/preprocessing"
+	"freectl/internal/preprocessing/extractors"
+	"freectl/internal/search"
 	"freectl/internal/settings"
 	"freectl/internal/sources"
 
@@ -16,6 +21,8 @@ var (
 	sourceName string
 	force      bool
 	parallel   bool
+	strategies []string
+	checkLinks bool
 )
 
 // ProcessCmd represents the process command
@@ -26,24 +33,34 @@ var ProcessCmd = &cobra.Command{
 
 This command preprocesses raw data sources (markdown files, RSS feeds, etc.)
 into a structured JSON format that can be searched much more efficiently.
+Only files that changed since the last run are processed again.
 
 Examples:
   freectl process                    # Process all enabled sources
   freectl process --source awesome-piracy  # Process specific source
-  freectl process --force            # Force reprocessing even if up-to-date
-  freectl process --parallel=false   # Process sources sequentially`,
+  freectl process --force            # Reprocess every file even if up-to-date
+  freectl process --parallel=false   # Process sources sequentially
+  freectl process --force --strategy structured,regex  # Only use some extraction strategies
+  freectl process --check-links      # Also check links not checked in the last week`,
 	RunE: runProcess,
 }
 
 func init() {
 	ProcessCmd.Flags().StringVarP(&sourceName, "source", "s", "", "Process specific source by name")
-	ProcessCmd.Flags().BoolVarP(&force, "force", "f", false, "Force reprocessing even if source is up-to-date")
+	ProcessCmd.Flags().BoolVarP(&force, "force", "f", false, "Reprocess every file even if the source is up-to-date")
 	ProcessCmd.Flags().BoolVar(&parallel, "parallel", true, "Process sources in parallel")
+	ProcessCmd.Flags().BoolVar(&checkLinks, "check-links", false, "Check the links of processed sources (default from the checkLinks setting)")
+	ProcessCmd.Flags().StringSliceVar(&strategies, "strategy", nil, "Markdown extraction strategies to run: table, structured, regex, simple (default all)")
 }
 
 func runProcess(cmd *cobra.Command, args []string) error {
 	startTime := time.Now()
 
+	format, err := output.FromFlag(cmd)
+	if err != nil {
+		return err
+	}
+
 	// Load settings
 	s, err := settings.Load()
 	if err != nil {
@@ -59,6 +76,17 @@ func runProcess(cmd *cobra.Command, args []string) error {
 	// Create processing config from settings
 	config := preprocessing.DefaultProcessingConfig()
 	config.ParallelProcessing = parallel
+	config.CategoryOverrides = s.CategoryOverrides
+	config.ValidateURLs = s.CheckLinks
+	if cmd.Flags().Changed("check-links") {
+		config.ValidateURLs = checkLinks
+	}
+	if len(strategies) > 0 {
+		if err := extractors.CheckStrategies(strategies); err != nil {
+			return err
+		}
+		config.ExtractionStrategies = strategies
+	}
 
 	// Create processing engine
 	engine := preprocessing.NewProcessingEngine(s.CacheDir, config)
@@ -69,8 +97,8 @@ func runProcess(cmd *cobra.Command, args []string) error {
 		var filteredSources []sources.Source
 		for _, source := range s.Sources {
 			if source.Name == sourceName {
-				// Check if processing is needed
-				if !force && !engine.NeedsProcessing(source) {
+				// Check if processing is needed, unless its links are to be checked
+				if !force && !config.ValidateURLs && !engine.NeedsProcessing(source) {
 					log.Info("Source is already up-to-date", "name", sourceName)
 					return nil
 				}
@@ -90,8 +118,8 @@ func runProcess(cmd *cobra.Command, args []string) error {
 	var sourcesToProcessFiltered []sources.Source
 	for _, source := range sourcesToProcess {
 		if source.Enabled || sourceName != "" {
-			// Check if processing is needed (unless forced)
-			if !force && sourceName == "" && !engine.NeedsProcessing(source) {
+			// Check if processing is needed (unless forced or checking links)
+			if !force && !config.ValidateURLs && sourceName == "" && !engine.NeedsProcessing(source) {
 				log.Debug("Skipping up-to-date source", "name", source.Name)
 				continue
 			}
@@ -105,20 +133,31 @@ func runProcess(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Process the sources
+	// Process the sources, including a disabled one asked for by name
 	log.Info("Processing sources", "count", len(sourcesToProcessFiltered), "parallel", parallel)
+	for i := range sourcesToProcessFiltered {
+		sourcesToProcessFiltered[i].Enabled = true
+	}
 
-	var processed, failed int
-	for _, source := range sourcesToProcessFiltered {
-		log.Info("Processing source", "name", source.Name, "type", source.Type)
+	processAll := engine.ProcessAllSources
+	if force {
+		processAll = engine.ReprocessAllSources
+	}
+	showProgress := format == output.FormatTable && output.IsTerminal()
+	if showProgress {
+		runWithProgressBar(engine, func() { processAll(sourcesToProcessFiltered) })
+	} else {
+		processAll(sourcesToProcessFiltered)
+	}
 
-		if err := engine.ProcessSource(source); err != nil {
-			log.Error("Failed to process source", "name", source.Name, "error", err)
-			failed++
-			continue
+	progress := engine.Progress()
+	failed := len(progress.Failed())
+	processed := len(progress.Sources) - failed
+	if showProgress {
+		// The engine's own log lines were hidden behind the progress bar
+		for _, status := range progress.Failed() {
+			log.Error("Failed to process source", "name", status.SourceName, "error", status.Error)
 		}
-
-		processed++
 	}
 
 	processingTime := time.Since(startTime)
@@ -131,8 +170,17 @@ func runProcess(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("processing completed with %d failures", failed)
 	}
 
+	// Re-run saved searches so their feeds pick up what was processed
+	found, err := search.RefreshSavedSearches(cmd.Context(), search.NewSearcher(s))
+	if err != nil {
+		log.Warn("Failed to refresh saved searches", "error", err)
+	}
+	if found > 0 {
+		log.Info("Saved searches found new results", "count", found)
+	}
+
 	// Show processing status
-	if err := showProcessingStatus(engine); err != nil {
+	if err := showProcessingStatus(engine, format); err != nil {
 		log.Warn("Failed to show processing status", "error", err)
 	}
 
@@ -140,12 +188,23 @@ func runProcess(cmd *cobra.Command, args []string) error {
 }
 
 // showProcessingStatus displays the current processing status
-func showProcessingStatus(engine *preprocessing.ProcessingEngine) error {
+func showProcessingStatus(engine *preprocessing.ProcessingEngine, format output.Format) error {
 	statuses, err := engine.GetProcessingStatus()
 	if err != nil {
 		return fmt.Errorf("failed to get processing status: %w", err)
 	}
 
+	if format != output.FormatTable || !output.IsTerminal() {
+		columns := []string{"SOURCE", "STATUS", "ITEMS", "COMPLETED", "ERROR"}
+		return output.Write(os.Stdout, format, columns, statuses, func(status preprocessing.ProcessingStatus) []string {
+			completed := ""
+			if status.CompletedAt != nil {
+				completed = status.CompletedAt.Format("2006-01-02 15:04:05")
+			}
+			return []string{status.SourceName, status.Status, strconv.Itoa(status.ItemsProcessed), completed, status.Error}
+		})
+	}
+
 	if len(statuses) == 0 {
 		log.Info("No processed sources found")
 		return nil
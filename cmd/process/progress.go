@@ -0,0 +1,96 @@
+package process
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"freectl/internal/preprocessing"
+
+	"github.com/charmbracelet/log"
+)
+
+// progressBarWidth is the number of cells in the progress bar
+const progressBarWidth = 30
+
+// maxActivityLength keeps the progress line on one line of most terminals
+const maxActivityLength = 40
+
+// runWithProgressBar runs process while drawing a progress bar for the
+// engine's run. Log lines would break up the bar, so they are discarded
+// until process returns.
+func runWithProgressBar(engine *preprocessing.ProcessingEngine, process func()) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stdout)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		process()
+	}()
+
+	// Redraw on changes, but no more often than the ticker allows
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		updates := engine.Updates()
+		fmt.Fprint(os.Stdout, "\r\033[K"+progressLine(engine.Progress()))
+
+		select {
+		case <-done:
+			fmt.Fprint(os.Stdout, "\r\033[K"+progressLine(engine.Progress())+"\n")
+			return
+		case <-updates:
+		}
+		select {
+		case <-done:
+		case <-ticker.C:
+		}
+	}
+}
+
+// progressLine renders a run's progress as a bar followed by what it's doing
+func progressLine(progress preprocessing.ProcessingProgress) string {
+	fraction := progress.Fraction()
+	filled := int(fraction * progressBarWidth)
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", progressBarWidth-filled)
+
+	done := 0
+	for _, status := range progress.Sources {
+		if status.Done() {
+			done++
+		}
+	}
+
+	line := fmt.Sprintf("%s %3.0f%%  %d/%d sources", bar, fraction*100, done, len(progress.Sources))
+	if activity := currentActivity(progress); activity != "" {
+		line += "  " + activity
+	}
+	return line
+}
+
+// currentActivity describes the first source being processed, such as
+// "awesome-go: extracting 3/12 files"
+func currentActivity(progress preprocessing.ProcessingProgress) string {
+	for _, status := range progress.Sources {
+		if status.Status != "processing" {
+			continue
+		}
+
+		activity := status.SourceName + ": " + status.Stage
+		switch status.Stage {
+		case preprocessing.StageExtracting:
+			activity += fmt.Sprintf(" %d/%d files", status.FilesProcessed, status.FilesTotal)
+		case preprocessing.StageChecking:
+			activity += fmt.Sprintf(" %d/%d", status.LinksChecked, status.LinksTotal)
+		}
+
+		if runes := []rune(activity); len(runes) > maxActivityLength {
+			activity = string(runes[:maxActivityLength-1]) + "…"
+		}
+		return activity
+	}
+	return ""
+}
@@ -1,17 +1,26 @@
 package root
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 
 	"freectl/cmd/add"
+	"freectl/cmd/checklinks"
 	"freectl/cmd/delete"
+	"freectl/cmd/favorites"
+	"freectl/cmd/history"
 	"freectl/cmd/list"
+	"freectl/cmd/open"
 	"freectl/cmd/process"
+	"freectl/cmd/rules"
 	"freectl/cmd/search"
 	"freectl/cmd/serve"
 	"freectl/cmd/stats"
+	"freectl/cmd/tui"
 	"freectl/cmd/update"
+	"freectl/internal/output"
 
 	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
@@ -31,15 +40,23 @@ func init() {
 
 	log.Debug("Initializing root command")
 
+	RootCmd.PersistentFlags().StringP("output", "o", string(output.FormatTable), "Output format: table, json, jsonl, csv, tsv or markdown")
+
 	// Add commands
 	RootCmd.AddCommand(add.AddCmd)
+	RootCmd.AddCommand(checklinks.CheckLinksCmd)
 	RootCmd.AddCommand(delete.DeleteCmd)
+	RootCmd.AddCommand(favorites.FavoritesCmd)
+	RootCmd.AddCommand(history.HistoryCmd)
 	RootCmd.AddCommand(list.ListCmd)
+	RootCmd.AddCommand(open.OpenCmd)
 	RootCmd.AddCommand(process.ProcessCmd)
+	RootCmd.AddCommand(rules.RulesCmd)
 	RootCmd.AddCommand(search.SearchCmd)
 	RootCmd.AddCommand(serve.ServeCmd)
 	RootCmd.AddCommand(update.UpdateCmd)
 	RootCmd.AddCommand(stats.StatsCmd)
+	RootCmd.AddCommand(tui.TuiCmd)
 
 	log.Debug("Root command initialization complete")
 }
@@ -62,9 +79,15 @@ Commands:
   process - Process sources into unified JSON format
   search  - Search through all cached repositories
   serve   - Start a web interface for searching
+  tui     - Full-screen terminal interfaces, such as the source manager
   update  - Update all cached repositories
 
+Running freectl without a command opens the interactive search.
+
 Examples:
+  # Search interactively
+  freectl
+
   # Add a new repository
   freectl add https://github.com/awesome-selfhosted/awesome-selfhosted --name "awesome-selfhosted"
 
@@ -84,16 +107,39 @@ Examples:
   freectl serve
 
   # Update all repositories
-  freectl update`,
+  freectl update
+
+  # Print results for scripts, in any of table, json, jsonl, csv, tsv or markdown
+  freectl search "torrent" --output json`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		format, err := output.FromFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		// Keep log lines out of output that is meant to be parsed
+		if format != output.FormatTable || !output.IsTerminal() {
+			log.SetOutput(os.Stderr)
+		}
+		return nil
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		log.Debug("Root command executed")
-		return cmd.Help()
+		if !output.IsTerminal() {
+			return cmd.Help()
+		}
+		return search.RunLive(cmd.Context())
 	},
 }
 
 func Execute() {
 	log.Debug("Starting command execution")
-	if err := RootCmd.Execute(); err != nil {
+
+	// Cancel in-flight work such as searches when the user interrupts
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := RootCmd.ExecuteContext(ctx); err != nil {
 		log.Error("Command execution failed", "error", err)
 		fmt.Println(err)
 		os.Exit(1)
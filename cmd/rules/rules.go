@@ -0,0 +1,280 @@
+package rules
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"freectl/internal/output"
+	"freectl/internal/preprocessing"
+	"freectl/internal/settings"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	force       bool
+	name        string
+	description string
+	headings    []string
+)
+
+// RulesCmd represents the rules command
+var RulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "List the categorisation and tagging rules",
+	Long: `List the rules that categorise and tag items when sources are processed.
+
+Rules are read from ~/.config/freectl/rules.json, in order. A rule matches an
+item when all of its conditions do:
+  domains   the URL's host or a subdomain of it
+  url       a regular expression the URL must match
+  headings  a heading above the item, ignoring case
+  keywords  a word or phrase in the item's name or description
+
+The first matching rule with a category decides it, replacing the list's own
+category unless the rule is a fallback. Every matching rule adds its tags, and
+a rule with "stop" set skips the rules after it. Without a rules file the
+default rules are used. Sources are processed again when the rules change.
+
+Examples:
+  # List the rules
+  freectl rules
+
+  # Write the default rules to the rules file to edit them
+  freectl rules init
+
+  # Show which rules match a URL
+  freectl rules test https://github.com/neovim/neovim`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := output.FromFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		rules, err := preprocessing.LoadRules()
+		if err != nil {
+			return fmt.Errorf("failed to load rules: %w", err)
+		}
+
+		return writeRules(format, rules, nil)
+	},
+}
+
+// initCmd represents the rules init command
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Write the default rules to the rules file",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := preprocessing.GetRulesPath()
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(path); err == nil && !force {
+			return fmt.Errorf("rules file %s already exists, use --force to replace it", path)
+		}
+
+		if err := preprocessing.SaveRules(preprocessing.DefaultRules()); err != nil {
+			return fmt.Errorf("failed to save rules: %w", err)
+		}
+
+		log.Info("Wrote the default rules", "path", path)
+		return nil
+	},
+}
+
+// testResult is what rules test reports
+type testResult struct {
+	URL      string               `json:"url"`
+	Name     string               `json:"name"`
+	Headings []string             `json:"headings,omitempty"`
+	Category string               `json:"category"`
+	Tags     []string             `json:"tags"`
+	Rules    []preprocessing.Rule `json:"rules"` // the matching rules, in order
+}
+
+// testCmd represents the rules test command
+var testCmd = &cobra.Command{
+	Use:   "test <url>",
+	Short: "Show which rules match a URL",
+	Long: `Show which rules match a URL and the category and tags they give it.
+
+The name, description and headings of the URL are taken from processed
+sources when it was found in one, and can be set with flags to try others.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := output.FromFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		rules, err := preprocessing.LoadRules()
+		if err != nil {
+			return fmt.Errorf("failed to load rules: %w", err)
+		}
+
+		item := preprocessing.RawItem{URL: args[0], Metadata: map[string]interface{}{}}
+		if processed, ok := findProcessedItem(args[0]); ok {
+			item.Name = processed.Name
+			item.Description = processed.Description
+			item.HeadingContext = processed.Metadata.HeadingHierarchy
+		}
+		if cmd.Flags().Changed("name") {
+			item.Name = name
+		}
+		if cmd.Flags().Changed("description") {
+			item.Description = description
+		}
+		if cmd.Flags().Changed("heading") {
+			item.HeadingContext = headings
+		}
+		if item.Name == "" {
+			item.Name = item.URL
+		}
+		item.Metadata["hierarchy"] = item.HeadingContext
+		if len(item.HeadingContext) > 0 {
+			item.Metadata["category"] = item.HeadingContext[len(item.HeadingContext)-1]
+		}
+
+		// Clean the item as processing would, for its final category and tags
+		config := preprocessing.DefaultProcessingConfig()
+		if s, err := settings.LoadSettings(); err == nil {
+			config.CategoryOverrides = s.CategoryOverrides
+		}
+		validator := preprocessing.NewDefaultValidator(config, rules)
+		cleaned := validator.Clean(item)
+		match := rules.Match(preprocessing.RuleInput{
+			URL:         item.URL,
+			Name:        item.Name,
+			Description: item.Description,
+			Headings:    item.HeadingContext,
+		})
+
+		result := testResult{
+			URL:      item.URL,
+			Name:     item.Name,
+			Headings: item.HeadingContext,
+			Category: cleaned.Category,
+			Tags:     cleaned.Tags,
+			Rules:    []preprocessing.Rule{},
+		}
+		for _, i := range match.Matched {
+			result.Rules = append(result.Rules, rules.Rules[i])
+		}
+
+		if format == output.FormatJSON || format == output.FormatJSONL {
+			return output.Write(os.Stdout, format, nil, []testResult{result}, nil)
+		}
+
+		if format == output.FormatTable {
+			fmt.Printf("URL:      %s\n", result.URL)
+			fmt.Printf("Name:     %s\n", result.Name)
+			if len(result.Headings) > 0 {
+				fmt.Printf("Headings: %s\n", strings.Join(result.Headings, " > "))
+			}
+			fmt.Printf("Category: %s\n", result.Category)
+			fmt.Printf("Tags:     %s\n\n", strings.Join(result.Tags, ", "))
+			if len(match.Matched) == 0 {
+				fmt.Println("No rules match")
+				return nil
+			}
+		}
+
+		return writeRules(format, rules, match.Matched)
+	},
+}
+
+// ruleRecord is a rule with its position in the rules file
+type ruleRecord struct {
+	Number int `json:"number"`
+	preprocessing.Rule
+}
+
+// writeRules prints the rules at indexes, or all of them when indexes is nil
+func writeRules(format output.Format, rules *preprocessing.RuleSet, indexes []int) error {
+	if indexes == nil {
+		for i := range rules.Rules {
+			indexes = append(indexes, i)
+		}
+	}
+
+	records := make([]ruleRecord, len(indexes))
+	for i, index := range indexes {
+		records[i] = ruleRecord{Number: index + 1, Rule: rules.Rules[index]}
+	}
+
+	columns := []string{"#", "NAME", "WHEN", "CATEGORY", "TAGS"}
+	return output.Write(os.Stdout, format, columns, records, func(r ruleRecord) []string {
+		category := r.Category
+		if category != "" && r.Fallback {
+			category += " (fallback)"
+		}
+		return []string{strconv.Itoa(r.Number), r.Name, describeConditions(r.Rule), category, strings.Join(r.Tags, ", ")}
+	})
+}
+
+// describeConditions summarises when a rule matches
+func describeConditions(rule preprocessing.Rule) string {
+	var conditions []string
+	if len(rule.Domains) > 0 {
+		conditions = append(conditions, "domain "+strings.Join(rule.Domains, "|"))
+	}
+	if rule.URL != "" {
+		conditions = append(conditions, "url ~ "+rule.URL)
+	}
+	if len(rule.Headings) > 0 {
+		conditions = append(conditions, "heading "+strings.Join(rule.Headings, "|"))
+	}
+	if len(rule.Keywords) > 0 {
+		conditions = append(conditions, "keyword "+strings.Join(rule.Keywords, "|"))
+	}
+	if len(conditions) == 0 {
+		return "always"
+	}
+	return strings.Join(conditions, " and ")
+}
+
+// findProcessedItem looks for a URL in the processed sources
+func findProcessedItem(url string) (preprocessing.ProcessedItem, bool) {
+	s, err := settings.LoadSettings()
+	if err != nil {
+		return preprocessing.ProcessedItem{}, false
+	}
+
+	storage := preprocessing.NewFileStorage(filepath.Join(s.CacheDir, "processed"))
+	names, err := storage.List()
+	if err != nil {
+		log.Debug("Failed to list processed sources", "error", err)
+		return preprocessing.ProcessedItem{}, false
+	}
+
+	want := strings.TrimSuffix(url, "/")
+	for _, name := range names {
+		processed, err := storage.Load(name)
+		if err != nil {
+			log.Debug("Failed to load processed source", "name", name, "error", err)
+			continue
+		}
+		for _, item := range processed.Items {
+			if strings.TrimSuffix(item.URL, "/") == want {
+				return item, true
+			}
+		}
+	}
+	return preprocessing.ProcessedItem{}, false
+}
+
+func init() {
+	initCmd.Flags().BoolVarP(&force, "force", "f", false, "Replace an existing rules file")
+	testCmd.Flags().StringVar(&name, "name", "", "Name to match instead of the processed one")
+	testCmd.Flags().StringVar(&description, "description", "", "Description to match instead of the processed one")
+	testCmd.Flags().StringSliceVar(&headings, "heading", nil, "Headings above the item, outermost first")
+
+	RulesCmd.AddCommand(initCmd)
+	RulesCmd.AddCommand(testCmd)
+}
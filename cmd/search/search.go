@@ -1,9 +1,16 @@
 package search
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"time"
 
 	"freectl/internal/common"
+	"freectl/internal/output"
 	"freectl/internal/search"
 	"freectl/internal/settings"
 	"freectl/internal/tui"
@@ -15,6 +22,11 @@ import (
 
 var sourceName string
 var usePreprocessed bool
+var incremental bool
+var saveAs string
+var savedName string
+var onlyNew bool
+var deadLinks string
 
 var SearchCmd = &cobra.Command{
 	Use:   "search [query]",
@@ -31,13 +43,31 @@ The search will:
 
 By default, only the top 10 results are shown. Use --limit to show more results.
 
+When the output is not a terminal, or --output is given, results are printed
+instead of opening the terminal UI, e.g. for piping into jq or fzf.
+
+Without a query, an interactive search opens instead: type in the input at
+the top and the results refresh once you pause typing (see the searchDelay
+setting). Running 'freectl' on its own does the same.
+
+With --incremental the terminal UI opens immediately and fills in with each
+source's best matches as they are found, re-ranking once every source has
+been searched.
+
 Controls:
   ? - Toggle help menu
   q - Quit
   ↑/↓ - Navigate results
-  enter - Select result
+  enter - Open the selected results, or the highlighted one
+  space - Select a result to open with others
+  f - Toggle favorite
+  y - Copy URL (works over SSH in terminals supporting OSC 52)
+  p - Toggle the preview pane
 
 Examples:
+  # Search interactively, refining the query as you type
+  freectl search
+
   # Search across all sources
   freectl search "torrent"
 
@@ -45,16 +75,30 @@ Examples:
   freectl search "kanban" --source "awesome-selfhosted"
 
   # Search with multiple words and limit results
-  freectl search --limit 20 "free movies streaming"`,
-	Args: cobra.ExactArgs(1),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) == 0 {
-			return fmt.Errorf("search query required")
-		}
+  freectl search --limit 20 "free movies streaming"
 
-		query := args[0]
-		log.Debug("Starting search", "query", query)
+  # Show results as soon as each source has been searched
+  freectl search --incremental "self-hosted wiki"
 
+  # Print results as JSON
+  freectl search --output json "torrent" | jq '.[].url'
+
+  # Leave out links found dead by 'freectl check-links'
+  freectl search --dead-links hide "torrent"
+
+  # Save a search, then later show only what's been added since
+  freectl search --save wikis "self-hosted wiki"
+  freectl search --saved wikis --new`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if savedName != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		if saveAs != "" {
+			return cobra.ExactArgs(1)(cmd, args)
+		}
+		return cobra.MaximumNArgs(1)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Load settings to get cache directory
 		s, err := settings.LoadSettings()
 		if err != nil {
@@ -62,62 +106,85 @@ Examples:
 			return fmt.Errorf("failed to load settings: %w", err)
 		}
 
-		// Perform search
-		var results []search.Result
+		if cmd.Flags().Changed("dead-links") {
+			if !slices.Contains([]string{search.DeadLinksShow, search.DeadLinksDemote, search.DeadLinksHide}, deadLinks) {
+				return fmt.Errorf("invalid --dead-links '%s', use show, demote or hide", deadLinks)
+			}
+			s.DeadLinks = deadLinks
+		}
+
+		// Print plain results for scripts instead of starting the TUI
+		format, err := output.FromFlag(cmd)
+		if err != nil {
+			return err
+		}
+		interactive := output.IsTerminal() && !cmd.Flags().Changed("output")
+
+		if onlyNew && savedName == "" && saveAs == "" {
+			return fmt.Errorf("--new only applies to saved searches, use it with --saved")
+		}
+
+		// --preprocessed overrides the searcher chosen in the settings
+		if cmd.Flags().Changed("preprocessed") {
+			s.UsePreprocessedSearch = usePreprocessed
+		}
+
+		if len(args) == 0 && savedName == "" {
+			if !interactive {
+				return fmt.Errorf("search query required when output is not an interactive terminal")
+			}
+			limit, _ := cmd.Flags().GetInt("limit")
+			return runLiveSearch(cmd.Context(), s, sourceName, limit)
+		}
 
 		// Get limit from flag
 		limit, _ := cmd.Flags().GetInt("limit")
+		if limit == 0 {
+			limit = 10
+		}
+
+		searcher := search.NewSearcher(s)
 
-		if usePreprocessed {
-			log.Info("Using preprocessed search")
-			results, err = search.SearchPreprocessed(query, sourceName, s, limit)
+		var results []search.Result
+		if saveAs != "" || savedName != "" {
+			results, err = runSavedSearch(cmd.Context(), searcher, args, limit)
+			if err != nil {
+				return err
+			}
 		} else {
-			log.Info("Using real-time search")
-			results, err = search.Search(query, sourceName, s)
-		}
+			query := args[0]
+			log.Debug("Starting search", "query", query)
 
-		if err != nil {
-			return fmt.Errorf("search failed: %w", err)
+			req := search.Request{Query: query, PerPage: limit}
+			if sourceName != "" {
+				req.Filters.Sources = []string{sourceName}
+			}
+
+			if interactive && incremental && !s.UsePreprocessedSearch {
+				return runIncrementalSearch(cmd.Context(), req, s)
+			}
+
+			log.Info("Searching", "searcher", searcher.Name())
+			resp, err := searcher.Search(cmd.Context(), req)
+			if err != nil {
+				return fmt.Errorf("search failed: %w", err)
+			}
+
+			log.Info("Search completed", "results", resp.TotalResults, "shown", len(resp.Results))
+			recordSearch(req, resp.TotalResults)
+			results = resp.Results
 		}
 
-		log.Info("Search completed", "results", len(results))
+		if !interactive {
+			return writeResults(os.Stdout, format, results)
+		}
 		if len(results) == 0 {
 			log.Info("No results found")
 			return nil
 		}
 
-		// Limit results (only for real-time search, preprocessed search handles this internally)
-		if !usePreprocessed {
-			if limit > 0 && limit < len(results) {
-				log.Info("Limiting results", "limit", limit, "total", len(results))
-				results = results[:limit]
-			} else if limit == 0 && len(results) > 10 {
-				log.Info("Applying default limit of 10 results", "total", len(results))
-				results = results[:10]
-			}
-		}
-
 		// Convert results to TUI format
-		tuiResults := make([]tui.SearchResult, len(results))
-		for i, r := range results {
-			tuiResults[i] = tui.SearchResult{
-				Category:  r.Category,
-				Link:      r.URL,
-				Name:      r.Name,
-				Line:      r.Line,
-				Score:     r.Score,
-				Source:    r.Source,
-				IsInvalid: common.IsInvalidCategory(r.Category),
-			}
-			log.Debug("Converted result",
-				"index", i,
-				"name", r.Name,
-				"description", r.Description,
-				"category", r.Category,
-				"url", r.URL,
-				"score", r.Score,
-				"source", r.Source)
-		}
+		tuiResults := toTUIResults(results)
 
 		log.Info("Starting TUI", "results", len(tuiResults))
 		// Create and run the TUI
@@ -130,8 +197,240 @@ Examples:
 	},
 }
 
+// runSavedSearch saves the query given with --save, or re-runs the one named
+// with --saved, returning the results to show. With --new only those no
+// earlier run found are returned.
+func runSavedSearch(ctx context.Context, searcher search.Searcher, args []string, limit int) ([]search.Result, error) {
+	name := savedName
+	if saveAs != "" {
+		var filters search.SearchFilters
+		if sourceName != "" {
+			filters.Sources = []string{sourceName}
+		}
+		if err := search.SaveSearch(saveAs, args[0], filters); err != nil {
+			return nil, fmt.Errorf("failed to save search: %w", err)
+		}
+		log.Info("Saved search", "name", saveAs, "query", args[0])
+		name = saveAs
+	}
+
+	log.Info("Running saved search", "name", name, "searcher", searcher.Name())
+	run, err := search.RunSavedSearch(ctx, searcher, name)
+	if err != nil {
+		return nil, fmt.Errorf("saved search failed: %w", err)
+	}
+
+	resp := run.Response
+	log.Info("Search completed", "query", run.Search.Query, "results", resp.TotalResults, "new", len(run.New))
+	recordSearch(search.Request{Query: run.Search.Query, Filters: run.Search.Filters}, resp.TotalResults)
+
+	if onlyNew {
+		if len(run.New) == 0 {
+			log.Info("No new results since the last run")
+		}
+		return run.New, nil
+	}
+
+	results := resp.Results
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// recordSearch adds a search to the history, which only warns on failure as
+// the search itself succeeded
+func recordSearch(req search.Request, total int) {
+	if err := search.RecordSearch(req.Query, req.Filters, total); err != nil {
+		log.Warn("Failed to record search history", "error", err)
+	}
+}
+
+// resultRecord is a search result as printed by --output
+type resultRecord struct {
+	Name         string   `json:"name"`
+	URL          string   `json:"url"`
+	Description  string   `json:"description"`
+	Category     string   `json:"category"`
+	CategoryPath []string `json:"category_path,omitempty"`
+	Source       string   `json:"source"`
+	Sources      []string `json:"sources,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+	Score        int      `json:"score"`
+	AppearsIn    int      `json:"appears_in"`
+	FilePath     string   `json:"file_path,omitempty"`
+	LineNumber   int      `json:"line_number,omitempty"`
+	SourceURL    string   `json:"source_url,omitempty"`
+	LinkStatus   int      `json:"link_status,omitempty"`
+	Dead         bool     `json:"dead,omitempty"`
+}
+
+// writeResults prints search results in a machine-readable format
+func writeResults(w io.Writer, format output.Format, results []search.Result) error {
+	records := make([]resultRecord, len(results))
+	for i, r := range results {
+		var sources []string
+		for _, a := range r.Appearances {
+			if !slices.Contains(sources, a.Source) {
+				sources = append(sources, a.Source)
+			}
+		}
+
+		records[i] = resultRecord{
+			Name:         r.Name,
+			URL:          r.URL,
+			Description:  r.Description,
+			Category:     r.Category,
+			CategoryPath: r.CategoryPath,
+			Source:       r.Source,
+			Sources:      sources,
+			Tags:         r.Tags,
+			Score:        r.Score,
+			AppearsIn:    r.AppearsIn,
+			FilePath:     r.FilePath,
+			LineNumber:   r.LineNumber,
+			SourceURL:    r.SourceURL,
+			LinkStatus:   r.LinkStatus,
+			Dead:         r.Dead,
+		}
+	}
+
+	columns := []string{"NAME", "URL", "CATEGORY", "SOURCE", "SCORE", "DESCRIPTION"}
+	return output.Write(w, format, columns, records, func(r resultRecord) []string {
+		return []string{r.Name, r.URL, r.Category, r.Source, strconv.Itoa(r.Score), r.Description}
+	})
+}
+
+// toTUIResults converts search results to the TUI format
+func toTUIResults(results []search.Result) []tui.SearchResult {
+	tuiResults := make([]tui.SearchResult, len(results))
+	for i, r := range results {
+		var sources []string
+		for _, a := range r.Appearances {
+			if !slices.Contains(sources, a.Source) {
+				sources = append(sources, a.Source)
+			}
+		}
+
+		tuiResults[i] = tui.SearchResult{
+			Category:     r.Category,
+			CategoryPath: r.CategoryPath,
+			Link:         r.URL,
+			Name:         r.Name,
+			Summary:      r.Description,
+			Line:         r.Line,
+			Score:        r.Score,
+			Source:       r.Source,
+			Sources:      sources,
+			AppearsIn:    r.AppearsIn,
+			IsInvalid:    common.IsInvalidCategory(r.Category),
+		}
+		log.Debug("Converted result",
+			"index", i,
+			"name", r.Name,
+			"description", r.Description,
+			"category", r.Category,
+			"url", r.URL,
+			"score", r.Score,
+			"source", r.Source)
+	}
+	return tuiResults
+}
+
+// RunLive opens the interactive search using the searcher selected in the
+// settings
+func RunLive(ctx context.Context) error {
+	s, err := settings.LoadSettings()
+	if err != nil {
+		return fmt.Errorf("failed to load settings: %w", err)
+	}
+	return runLiveSearch(ctx, s, "", 0)
+}
+
+// runLiveSearch opens the TUI with a query input that searches as the user
+// types, optionally restricted to a single source
+func runLiveSearch(ctx context.Context, s settings.Settings, source string, limit int) error {
+	if limit == 0 {
+		limit = s.ResultsPerPage
+	}
+
+	searcher := search.NewSearcher(s)
+	searchFn := func(ctx context.Context, query string) ([]tui.SearchResult, error) {
+		req := search.Request{Query: query, PerPage: limit}
+		if source != "" {
+			req.Filters.Sources = []string{source}
+		}
+
+		resp, err := searcher.Search(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		recordSearch(req, resp.TotalResults)
+		return toTUIResults(resp.Results), nil
+	}
+
+	// Log lines would be drawn over the TUI while searches run
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stdout)
+
+	delay := time.Duration(s.SearchDelay) * time.Millisecond
+	p := tea.NewProgram(tui.NewLiveModel(ctx, searchFn, "", delay, s.MinQueryLength))
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("error running TUI: %w", err)
+	}
+
+	return nil
+}
+
+// runIncrementalSearch opens the TUI straight away and streams each source's
+// results into it while the search runs in the background
+func runIncrementalSearch(ctx context.Context, req search.Request, s settings.Settings) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Log lines would be drawn over the TUI while the search runs
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stdout)
+
+	p := tea.NewProgram(tui.NewIncrementalModel(req.PerPage))
+
+	go func() {
+		var resp *search.Response
+		var err error
+
+		batches := make(chan search.SourceBatch)
+		go func() {
+			resp, err = search.NewLiveSearcher(s).SearchStream(ctx, req, batches)
+			close(batches)
+		}()
+
+		for batch := range batches {
+			p.Send(tui.ResultsBatchMsg{Results: toTUIResults(batch.Results)})
+		}
+
+		var results []search.Result
+		if resp != nil {
+			results = resp.Results
+		}
+		p.Send(tui.ResultsDoneMsg{Results: toTUIResults(results), Err: err})
+	}()
+
+	// Quitting the TUI cancels a search that is still running
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("error running TUI: %w", err)
+	}
+
+	return nil
+}
+
 func init() {
 	SearchCmd.Flags().StringVarP(&sourceName, "source", "r", "", "Search in a specific source")
 	SearchCmd.Flags().IntP("limit", "l", 0, "Maximum number of results to show (default: 10)")
-	SearchCmd.Flags().BoolVarP(&usePreprocessed, "preprocessed", "p", false, "Use preprocessed search (faster, requires 'freectl process' first)")
+	SearchCmd.Flags().BoolVarP(&usePreprocessed, "preprocessed", "p", false, "Use preprocessed search (faster, requires 'freectl process' first), overriding the usePreprocessedSearch setting")
+	SearchCmd.Flags().BoolVarP(&incremental, "incremental", "i", false, "Show results from each source as soon as they are found")
+	SearchCmd.Flags().StringVar(&saveAs, "save", "", "Save the query under a name to re-run it with --saved")
+	SearchCmd.Flags().StringVar(&savedName, "saved", "", "Re-run the saved search with this name")
+	SearchCmd.Flags().BoolVar(&onlyNew, "new", false, "With --saved, only show results not found by earlier runs")
+	SearchCmd.Flags().StringVar(&deadLinks, "dead-links", "", "Show, demote or hide results whose link was found dead (default from settings: demote)")
+	SearchCmd.MarkFlagsMutuallyExclusive("save", "saved")
 }
@@ -55,12 +55,28 @@ func startServer() error {
 	http.HandleFunc("/", web.HandleHome)
 	http.HandleFunc("/static/", web.HandleStatic)
 	http.HandleFunc("/search", web.HandleSearch)
+	http.HandleFunc("/search/stream", web.HandleSearchStream)
 	http.HandleFunc("/favorites", web.HandleFavorites)
 	http.HandleFunc("/favorites/add", web.HandleAddFavorite)
 	http.HandleFunc("/favorites/remove", web.HandleRemoveFavorite)
+	http.HandleFunc("/favorites/update", web.HandleUpdateFavorite)
+	http.HandleFunc("/favorites/move", web.HandleMoveFavorite)
+	http.HandleFunc("/favorites/search", web.HandleSearchFavorites)
+	http.HandleFunc("/favorites/export", web.HandleExportFavorites)
+	http.HandleFunc("/collections", web.HandleCollections)
+	http.HandleFunc("/collections/create", web.HandleCreateCollection)
+	http.HandleFunc("/collections/rename", web.HandleRenameCollection)
+	http.HandleFunc("/collections/delete", web.HandleDeleteCollection)
+	http.HandleFunc("/collections/add", web.HandleAddToCollection)
+	http.HandleFunc("/collections/remove", web.HandleRemoveFromCollection)
+	http.HandleFunc("/history", web.HandleHistory)
+	http.HandleFunc("/history/saved", web.HandleSavedSearches)
+	http.HandleFunc("/feeds/saved/", web.HandleSavedSearchFeed)
 	http.HandleFunc("/stats", web.HandleStats)
 	http.HandleFunc("/update", web.HandleUpdate)
 	http.HandleFunc("/process", web.HandleProcessSources)
+	http.HandleFunc("/process/status", web.HandleProcessStatus)
+	http.HandleFunc("/process/status/stream", web.HandleProcessStatusStream)
 	http.HandleFunc("/settings", web.HandleSettings)
 	http.HandleFunc("/sources/add", web.HandleAddSource)
 	http.HandleFunc("/sources/list", web.HandleListSource)
@@ -2,7 +2,10 @@ package stats
 
 import (
 	"fmt"
+	"os"
+	"strconv"
 
+	"freectl/internal/output"
 	"freectl/internal/settings"
 	"freectl/internal/stats"
 
@@ -39,17 +42,44 @@ var StatsCmd = &cobra.Command{
 			return fmt.Errorf("failed to get source status: %w", err)
 		}
 
+		format, err := output.FromFlag(cmd)
+		if err != nil {
+			return err
+		}
+
 		// Print stats
-		log.Info("Source statistics",
-			"name", sourceName,
-			"size", formatSize(sourceStats.TotalSize),
-			"files", sourceStats.FileCount,
-			"enabled", enabled)
+		if format == output.FormatTable && output.IsTerminal() {
+			log.Info("Source statistics",
+				"name", sourceName,
+				"size", formatSize(sourceStats.TotalSize),
+				"files", sourceStats.FileCount,
+				"enabled", enabled)
+			return nil
+		}
 
-		return nil
+		record := statsRecord{
+			Name:      sourceName,
+			Size:      formatSize(sourceStats.TotalSize),
+			SizeBytes: sourceStats.TotalSize,
+			Files:     sourceStats.FileCount,
+			Enabled:   enabled,
+		}
+		columns := []string{"NAME", "SIZE", "FILES", "ENABLED"}
+		return output.Write(os.Stdout, format, columns, []statsRecord{record}, func(r statsRecord) []string {
+			return []string{r.Name, r.Size, strconv.FormatInt(r.Files, 10), strconv.FormatBool(r.Enabled)}
+		})
 	},
 }
 
+// statsRecord is a source's statistics as printed by --output
+type statsRecord struct {
+	Name      string `json:"name"`
+	Size      string `json:"size"`
+	SizeBytes int64  `json:"size_bytes"`
+	Files     int64  `json:"files"`
+	Enabled   bool   `json:"enabled"`
+}
+
 // formatSize formats a size in bytes to a human-readable string
 func formatSize(size int64) string {
 	const unit = 1024
@@ -0,0 +1,56 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"freectl/internal/tui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+)
+
+// TuiCmd groups the full-screen terminal interfaces
+var TuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Full-screen terminal interfaces",
+	Long: `Full-screen terminal interfaces for managing freectl.
+
+Use 'freectl search' or run 'freectl' on its own to search interactively.`,
+}
+
+// sourcesCmd represents the tui sources command
+var sourcesCmd = &cobra.Command{
+	Use:   "sources",
+	Short: "Manage data sources in a full-screen terminal UI",
+	Long: `Manage data sources in a full-screen terminal UI.
+
+Controls:
+  ↑/↓ - Navigate sources
+  space - Enable or disable the source
+  u - Update the source
+  a - Add a source
+  r - Rename the source
+  d - Delete the source
+  ? - Toggle help menu
+  q - Quit`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Log lines would be drawn over the TUI while sources are updated
+		log.SetOutput(io.Discard)
+		defer log.SetOutput(os.Stdout)
+
+		p := tea.NewProgram(tui.NewSourcesModel(), tea.WithAltScreen())
+		if _, err := p.Run(); err != nil {
+			return fmt.Errorf("error running TUI: %w", err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	TuiCmd.AddCommand(sourcesCmd)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"freectl/internal/search"
 	"freectl/internal/settings"
 
 	"github.com/charmbracelet/log"
@@ -39,6 +40,19 @@ content from each source.`,
 
 		duration := time.Since(startTime).Round(100 * time.Millisecond)
 		log.Info("Update completed", "duration", duration)
+
+		// Re-run saved searches so their feeds pick up what was added
+		s, err := settings.LoadSettings()
+		if err != nil {
+			return fmt.Errorf("failed to load settings: %w", err)
+		}
+		found, err := search.RefreshSavedSearches(cmd.Context(), search.NewSearcher(s))
+		if err != nil {
+			log.Warn("Failed to refresh saved searches", "error", err)
+		}
+		if found > 0 {
+			log.Info("Saved searches found new results", "count", found)
+		}
 		return nil
 	},
 }
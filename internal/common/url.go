@@ -0,0 +1,85 @@
+package common
+
+import (
+	"net/url"
+	"strings"
+)
+
+// trackingParams are query parameters that only say where a visitor came
+// from. Parameters starting with "utm_" are dropped too.
+var trackingParams = map[string]bool{
+	"ref": true, "ref_src": true, "ref_url": true,
+	"fbclid": true, "gclid": true, "mc_cid": true, "mc_eid": true,
+}
+
+// CanonicalURL returns the form of a URL that links to the same page share,
+// for comparing and deduplicating links. It isn't meant for display: the
+// scheme becomes https, the host is lower-cased without "www." or a default
+// port, tracking parameters, trailing slashes and fragments other than
+// routes such as "#/about" or "#!/about" are dropped, and GitHub repository
+// links lose their ".git" suffix, casing and branch path. URLs that can't be
+// parsed are returned trimmed.
+func CanonicalURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(strings.ToLower(raw), "www.") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return raw
+	}
+
+	host := strings.ToLower(u.Hostname())
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "www."), ".")
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host += ":" + port
+	}
+
+	path := strings.TrimRight(u.EscapedPath(), "/")
+	if host == "github.com" {
+		path = canonicalGitHubPath(path)
+	}
+
+	canonical := "https://" + host + path
+	if query := canonicalQuery(u.Query()); query != "" {
+		canonical += "?" + query
+	}
+
+	// Hash-routed single page apps keep their route in the fragment
+	if fragment := u.EscapedFragment(); strings.HasPrefix(fragment, "/") || strings.HasPrefix(fragment, "!") {
+		canonical += "#" + fragment
+	}
+	return canonical
+}
+
+// canonicalGitHubPath reduces the path of a link into a GitHub repository,
+// so "/Owner/Repo.git" and "/owner/repo/tree/main" both become "/owner/repo"
+func canonicalGitHubPath(path string) string {
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return strings.ToLower(path)
+	}
+
+	// Owners and repositories are case-insensitive, the paths in them aren't
+	parts[0] = strings.ToLower(parts[0])
+	parts[1] = strings.ToLower(strings.TrimSuffix(parts[1], ".git"))
+
+	// A branch without a path in it is the repository itself
+	if len(parts) == 4 && parts[2] == "tree" {
+		parts = parts[:2]
+	}
+
+	return "/" + strings.Join(parts, "/")
+}
+
+// canonicalQuery encodes query without tracking parameters, sorted by key
+func canonicalQuery(query url.Values) string {
+	for key := range query {
+		lower := strings.ToLower(key)
+		if strings.HasPrefix(lower, "utm_") || trackingParams[lower] {
+			query.Del(key)
+		}
+	}
+	return query.Encode()
+}
@@ -0,0 +1,37 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"scheme and www", "http://www.Example.com/", "https://example.com"},
+		{"bare www", "www.example.com/docs/", "https://example.com/docs"},
+		{"default port", "https://example.com:443/a", "https://example.com/a"},
+		{"other port", "http://example.com:8080/a", "https://example.com:8080/a"},
+		{"tracking parameters", "https://example.com/?utm_source=awesome&UTM_Medium=list&ref=github", "https://example.com"},
+		{"sorted query", "https://example.com/search?q=go&a=1&utm_campaign=x", "https://example.com/search?a=1&q=go"},
+		{"fragment", "https://example.com/page#install", "https://example.com/page"},
+		{"hash route", "https://example.com/#/docs/install", "https://example.com#/docs/install"},
+		{"hashbang route", "https://example.com/app/#!/settings", "https://example.com/app#!/settings"},
+		{"path case kept", "https://example.com/Docs/README", "https://example.com/Docs/README"},
+		{"github case and .git", "https://github.com/Neovim/Neovim.git", "https://github.com/neovim/neovim"},
+		{"github branch", "https://github.com/neovim/neovim/tree/master", "https://github.com/neovim/neovim"},
+		{"github file", "https://github.com/Neovim/neovim/blob/master/README.md", "https://github.com/neovim/neovim/blob/master/README.md"},
+		{"not http", "mailto:someone@example.com", "mailto:someone@example.com"},
+		{"relative", "/docs/", "/docs/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CanonicalURL(tt.input))
+		})
+	}
+}
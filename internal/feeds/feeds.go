@@ -0,0 +1,174 @@
+package feeds
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Feed is a syndication feed that can be written as Atom or RSS
+type Feed struct {
+	Title       string
+	Description string
+	Link        string // page the feed is about
+	Self        string // URL of the feed itself
+	Updated     time.Time
+	Entries     []Entry
+}
+
+// Entry is a single item in a feed
+type Entry struct {
+	Title    string
+	Link     string
+	Summary  string
+	Category string
+	Author   string
+	Updated  time.Time
+}
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	ID       string      `xml:"id"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Author   atomAuthor  `xml:"author"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	Title    string        `xml:"title"`
+	ID       string        `xml:"id"`
+	Updated  string        `xml:"updated"`
+	Link     atomLink      `xml:"link"`
+	Summary  string        `xml:"summary,omitempty"`
+	Category *atomCategory `xml:"category,omitempty"`
+	Author   *atomAuthor   `xml:"author,omitempty"`
+}
+
+// WriteAtom writes the feed as an Atom 1.0 document
+func WriteAtom(w io.Writer, feed Feed) error {
+	doc := atomFeed{
+		Title:    feed.Title,
+		Subtitle: feed.Description,
+		ID:       feed.Self,
+		Updated:  atomTime(feed.Updated),
+		Links: []atomLink{
+			{Href: feed.Self, Rel: "self", Type: "application/atom+xml"},
+			{Href: feed.Link, Rel: "alternate", Type: "text/html"},
+		},
+		Author: atomAuthor{Name: "freectl"},
+	}
+
+	for _, e := range feed.Entries {
+		entry := atomEntry{
+			Title:   e.Title,
+			ID:      e.Link,
+			Updated: atomTime(e.Updated),
+			Link:    atomLink{Href: e.Link},
+			Summary: e.Summary,
+		}
+		if e.Category != "" {
+			entry.Category = &atomCategory{Term: e.Category}
+		}
+		if e.Author != "" {
+			entry.Author = &atomAuthor{Name: e.Author}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	return writeXML(w, doc)
+}
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Self          atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	Description string  `xml:"description,omitempty"`
+	Category    string  `xml:"category,omitempty"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// WriteRSS writes the feed as an RSS 2.0 document
+func WriteRSS(w io.Writer, feed Feed) error {
+	doc := rssDocument{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         feed.Title,
+			Link:          feed.Link,
+			Description:   feed.Description,
+			LastBuildDate: feed.Updated.UTC().Format(time.RFC1123Z),
+			Self:          atomLink{Href: feed.Self, Rel: "self", Type: "application/rss+xml"},
+		},
+	}
+
+	for _, e := range feed.Entries {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       e.Title,
+			Link:        e.Link,
+			GUID:        rssGUID{Value: e.Link, IsPermaLink: true},
+			Description: e.Summary,
+			Category:    e.Category,
+			PubDate:     e.Updated.UTC().Format(time.RFC1123Z),
+		})
+	}
+
+	return writeXML(w, doc)
+}
+
+// atomTime formats a timestamp as Atom requires
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// writeXML writes an indented XML document with its declaration
+func writeXML(w io.Writer, doc any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode feed: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,66 @@
+package feeds
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFeed() Feed {
+	updated := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	return Feed{
+		Title:       "freectl: wikis",
+		Description: "New results for \"wiki\"",
+		Link:        "http://localhost:8080/",
+		Self:        "http://localhost:8080/feeds/saved/wikis.xml",
+		Updated:     updated,
+		Entries: []Entry{
+			{Title: "Wiki.js", Link: "https://js.wiki/", Summary: "A modern wiki & more", Category: "Wikis", Updated: updated},
+		},
+	}
+}
+
+func TestWriteAtom(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteAtom(&buf, testFeed()))
+
+	var doc struct {
+		Title   string `xml:"title"`
+		Updated string `xml:"updated"`
+		Entries []struct {
+			Title   string `xml:"title"`
+			ID      string `xml:"id"`
+			Summary string `xml:"summary"`
+		} `xml:"entry"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "freectl: wikis", doc.Title)
+	assert.Equal(t, "2024-05-01T12:00:00Z", doc.Updated)
+	require.Len(t, doc.Entries, 1)
+	assert.Equal(t, "https://js.wiki/", doc.Entries[0].ID)
+	assert.Equal(t, "A modern wiki & more", doc.Entries[0].Summary)
+	assert.Contains(t, buf.String(), `xmlns="http://www.w3.org/2005/Atom"`)
+}
+
+func TestWriteRSS(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteRSS(&buf, testFeed()))
+
+	var doc struct {
+		Version string `xml:"version,attr"`
+		Items   []struct {
+			Title   string `xml:"title"`
+			Link    string `xml:"link"`
+			PubDate string `xml:"pubDate"`
+		} `xml:"channel>item"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "2.0", doc.Version)
+	require.Len(t, doc.Items, 1)
+	assert.Equal(t, "https://js.wiki/", doc.Items[0].Link)
+	assert.Equal(t, "Wed, 01 May 2024 12:00:00 +0000", doc.Items[0].PubDate)
+}
@@ -0,0 +1,128 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+// Format is a way of printing command results
+type Format string
+
+// Supported output formats
+const (
+	FormatTable    Format = "table"
+	FormatJSON     Format = "json"
+	FormatJSONL    Format = "jsonl"
+	FormatCSV      Format = "csv"
+	FormatTSV      Format = "tsv"
+	FormatMarkdown Format = "markdown"
+)
+
+// Formats lists every supported output format
+var Formats = []Format{FormatTable, FormatJSON, FormatJSONL, FormatCSV, FormatTSV, FormatMarkdown}
+
+// ParseFormat validates the name of an output format
+func ParseFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(name, string(f)) {
+			return f, nil
+		}
+	}
+
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("unknown output format '%s', expected one of %s", name, strings.Join(names, ", "))
+}
+
+// FromFlag returns the format chosen with the global --output flag
+func FromFlag(cmd *cobra.Command) (Format, error) {
+	name, _ := cmd.Flags().GetString("output")
+	return ParseFormat(name)
+}
+
+// IsTerminal reports whether stdout is an interactive terminal. When it is
+// not, for example when piping into jq or fzf, commands print plain output
+// instead of starting a TUI.
+func IsTerminal() bool {
+	fd := os.Stdout.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// Write prints records in the given format. JSON and JSON Lines encode the
+// records themselves; the other formats print the columns produced by row.
+func Write[T any](w io.Writer, format Format, columns []string, records []T, row func(T) []string) error {
+	switch format {
+	case FormatJSON:
+		if records == nil {
+			records = []T{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+
+	case FormatJSONL:
+		encoder := json.NewEncoder(w)
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case FormatCSV, FormatTSV:
+		writer := csv.NewWriter(w)
+		if format == FormatTSV {
+			writer.Comma = '\t'
+		}
+		if err := writer.Write(columns); err != nil {
+			return err
+		}
+		for _, record := range records {
+			if err := writer.Write(row(record)); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+
+	case FormatMarkdown:
+		fmt.Fprintf(w, "| %s |\n", strings.Join(columns, " | "))
+		fmt.Fprintf(w, "|%s\n", strings.Repeat(" --- |", len(columns)))
+		for _, record := range records {
+			cells := row(record)
+			for i, cell := range cells {
+				cells[i] = escapeMarkdownCell(cell)
+			}
+			fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+		}
+		return nil
+
+	default:
+		writer := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(writer, strings.Join(columns, "\t"))
+		for _, record := range records {
+			cells := row(record)
+			for i, cell := range cells {
+				cells[i] = strings.ReplaceAll(cell, "\t", " ")
+			}
+			fmt.Fprintln(writer, strings.Join(cells, "\t"))
+		}
+		return writer.Flush()
+	}
+}
+
+// escapeMarkdownCell keeps a value from breaking out of its table cell
+func escapeMarkdownCell(cell string) string {
+	cell = strings.ReplaceAll(cell, "|", "\\|")
+	return strings.Join(strings.Fields(cell), " ")
+}
@@ -0,0 +1,78 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testRecord struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+func testRow(r testRecord) []string {
+	return []string{r.Name, r.URL}
+}
+
+func TestWrite(t *testing.T) {
+	records := []testRecord{
+		{Name: "Koala", URL: "https://koala.com/"},
+		{Name: "Kanga|roo, Jr", URL: "https://kangaroo.com/"},
+	}
+	columns := []string{"NAME", "URL"}
+
+	tests := []struct {
+		format   Format
+		expected string
+	}{
+		{
+			format: FormatTable,
+			expected: "NAME            URL\n" +
+				"Koala           https://koala.com/\n" +
+				"Kanga|roo, Jr   https://kangaroo.com/\n",
+		},
+		{
+			format:   FormatJSONL,
+			expected: "{\"name\":\"Koala\",\"url\":\"https://koala.com/\"}\n{\"name\":\"Kanga|roo, Jr\",\"url\":\"https://kangaroo.com/\"}\n",
+		},
+		{
+			format:   FormatCSV,
+			expected: "NAME,URL\nKoala,https://koala.com/\n\"Kanga|roo, Jr\",https://kangaroo.com/\n",
+		},
+		{
+			format:   FormatTSV,
+			expected: "NAME\tURL\nKoala\thttps://koala.com/\nKanga|roo, Jr\thttps://kangaroo.com/\n",
+		},
+		{
+			format: FormatMarkdown,
+			expected: "| NAME | URL |\n| --- | --- |\n" +
+				"| Koala | https://koala.com/ |\n" +
+				"| Kanga\\|roo, Jr | https://kangaroo.com/ |\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NoError(t, Write(&buf, tt.format, columns, records, testRow))
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestWriteEmptyJSON(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, Write[testRecord](&buf, FormatJSON, nil, nil, testRow))
+	assert.Equal(t, "[]\n", buf.String())
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("JSON")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatJSON, format)
+
+	_, err = ParseFormat("yaml")
+	assert.Error(t, err)
+}
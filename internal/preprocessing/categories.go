@@ -0,0 +1,237 @@
+package preprocessing
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// categorySeparator separates the levels of a canonical category path, such
+// as "Media > Video"
+const categorySeparator = ">"
+
+// canonicalCategories is the built-in category tree. Headings whose key
+// matches a synonym are filed under the path, so "Movies & TV" from one list
+// and "Streaming Sites" from another end up in the same category.
+var canonicalCategories = []struct {
+	path     string
+	synonyms []string
+}{
+	{"Media > Video", []string{"video", "movies", "movies and tv", "movies and shows", "tv", "tv shows", "film", "streaming", "video streaming", "streaming sites"}},
+	{"Media > Audio", []string{"audio", "music", "music streaming", "podcasts", "radio"}},
+	{"Media > Anime", []string{"anime", "anime streaming"}},
+	{"Reading > Books", []string{"books", "ebooks", "audiobooks", "literature", "reading"}},
+	{"Reading > Comics", []string{"comics", "manga"}},
+	{"Gaming", []string{"games", "gaming", "video games"}},
+	{"Gaming > Emulation", []string{"emulation", "emulators", "roms"}},
+	{"Downloading", []string{"downloading", "downloads", "direct download", "ddl"}},
+	{"Downloading > Torrenting", []string{"torrenting", "torrents", "torrent clients", "p2p"}},
+	{"Privacy & Security", []string{"privacy", "security", "privacy and security", "adblocking", "ad blocking", "vpn"}},
+	{"Development", []string{"development", "programming", "developer tools", "dev tools", "coding"}},
+	{"Education", []string{"education", "educational", "learning", "courses"}},
+	{"AI", []string{"ai", "artificial intelligence", "machine learning", "llms", "chatbots"}},
+	{"Linux", []string{"linux", "gnu linux", "gnu/linux"}},
+	{"Android", []string{"android", "android apps"}},
+	{"iOS", []string{"ios", "ios apps", "iphone"}},
+}
+
+// knownAcronyms are written in capitals wherever they appear in a heading
+var knownAcronyms = map[string]string{
+	"ai": "AI", "api": "API", "apis": "APIs", "cli": "CLI", "css": "CSS",
+	"ddl": "DDL", "dns": "DNS", "faq": "FAQ", "gui": "GUI", "html": "HTML",
+	"ide": "IDE", "ides": "IDEs", "iptv": "IPTV", "llm": "LLM", "llms": "LLMs",
+	"os": "OS", "p2p": "P2P", "pdf": "PDF", "rss": "RSS", "sdk": "SDK",
+	"sql": "SQL", "tv": "TV", "ui": "UI", "url": "URL", "vpn": "VPN", "vpns": "VPNs",
+	"ios": "iOS", "macos": "macOS",
+}
+
+// unchangedPlurals end in "s" without being plural
+var unchangedPlurals = map[string]bool{
+	"news": true, "series": true, "species": true, "status": true, "analysis": true,
+	"ios": true, "macos": true, "os": true, "windows": true, "dns": true, "aws": true,
+	"physics": true, "mathematics": true, "maths": true, "economics": true, "graphics": true,
+}
+
+var (
+	// categoryNumbering matches numbering before a heading, such as "1.",
+	// "2.3)" or "IV."
+	categoryNumbering = regexp.MustCompile(`^(?:\d+(?:\.\d+)*(?:[.):]\s*|\s+)|[IVXivx]+[.)]\s*)(?:[-–—:]\s*)?`)
+
+	// categoryShortcode matches emoji shortcodes such as ":tv:"
+	categoryShortcode = regexp.MustCompile(`:[a-z0-9_+-]+:`)
+)
+
+// CategoryNormalizer turns headings into consistent category names, mapping
+// synonymous headings from different lists onto a canonical category tree
+type CategoryNormalizer struct {
+	canonical map[string][]string // category key to canonical path
+}
+
+// NewCategoryNormalizer creates a normalizer for the built-in category tree.
+// overrides maps headings to the category path to file them under instead,
+// such as "Movies & Shows" to "Media > Video".
+func NewCategoryNormalizer(overrides map[string]string) *CategoryNormalizer {
+	cn := &CategoryNormalizer{canonical: make(map[string][]string)}
+	for _, category := range canonicalCategories {
+		path := splitCategoryPath(category.path)
+		cn.canonical[categoryKey(path[len(path)-1])] = path
+		for _, synonym := range category.synonyms {
+			cn.canonical[categoryKey(synonym)] = path
+		}
+	}
+
+	for heading, path := range overrides {
+		if key := categoryKey(CleanCategoryName(heading)); key != "" {
+			cn.canonical[key] = splitCategoryPath(path)
+		}
+	}
+
+	return cn
+}
+
+// Normalize returns the category for a heading and its parent in the
+// category tree. ok reports whether the heading is in the tree, otherwise
+// category is the cleaned heading.
+func (cn *CategoryNormalizer) Normalize(heading string) (category, parent string, ok bool) {
+	name := CleanCategoryName(heading)
+	if name == "" {
+		return "", "", false
+	}
+
+	if path, found := cn.canonical[categoryKey(name)]; found && len(path) > 0 {
+		category, parent = categoryFromPath(path)
+		return category, parent, true
+	}
+
+	return name, "", false
+}
+
+// categoryFromPath returns the category at the end of a path and its parent
+func categoryFromPath(path []string) (category, parent string) {
+	if len(path) == 0 {
+		return "", ""
+	}
+	if len(path) > 1 {
+		parent = path[len(path)-2]
+	}
+	return path[len(path)-1], parent
+}
+
+// splitCategoryPath splits a category path such as "Media > Video"
+func splitCategoryPath(path string) []string {
+	var levels []string
+	for _, level := range strings.Split(path, categorySeparator) {
+		if level = strings.TrimSpace(level); level != "" {
+			levels = append(levels, level)
+		}
+	}
+	return levels
+}
+
+// CleanCategoryName tidies a heading for display. It removes emoji and
+// numbering around it, capitalises its first word and known acronyms, and
+// otherwise keeps the heading's own casing, so "DNS" and "APIs" survive.
+func CleanCategoryName(heading string) string {
+	name := categoryShortcode.ReplaceAllString(heading, " ")
+	name = trimCategorySymbols(name)
+	name = categoryNumbering.ReplaceAllString(name, "")
+	name = trimCategorySymbols(name)
+
+	words := strings.Fields(name)
+	if len(words) == 0 {
+		return ""
+	}
+
+	// Headings in capitals are shouted rather than acronyms
+	shouted := name == strings.ToUpper(name) && name != strings.ToLower(name) && (len(words) > 1 || len(name) > 4)
+
+	for i, word := range words {
+		lower := strings.ToLower(word)
+		switch {
+		case knownAcronyms[lower] != "":
+			words[i] = knownAcronyms[lower]
+		case shouted:
+			words[i] = capitalise(lower)
+		case i == 0 && word == lower:
+			words[i] = capitalise(word)
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+// trimCategorySymbols removes emoji, bullets and other symbols around a
+// heading, keeping those that are part of a name such as ".NET" or "C++"
+func trimCategorySymbols(name string) string {
+	runes := []rune(strings.TrimSpace(name))
+
+	start := 0
+	for start < len(runes) {
+		r := runes[start]
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			break
+		}
+		if r == '.' && start+1 < len(runes) && unicode.IsLetter(runes[start+1]) {
+			break
+		}
+		start++
+	}
+
+	end := len(runes)
+	for end > start {
+		r := runes[end-1]
+		if !unicode.IsSpace(r) && !unicode.In(r, unicode.So, unicode.Sk, unicode.Cf, unicode.Mn) && !strings.ContainsRune(":-|*_`", r) {
+			break
+		}
+		end--
+	}
+
+	return strings.TrimSpace(string(runes[start:end]))
+}
+
+// capitalise upper-cases the first letter of word
+func capitalise(word string) string {
+	runes := []rune(word)
+	if len(runes) == 0 {
+		return word
+	}
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// categoryKey reduces a category name to what synonyms are compared by:
+// lower case, without punctuation, "&" as "and", and each word singular
+func categoryKey(name string) string {
+	name = strings.ToLower(strings.ReplaceAll(name, "&", " and "))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return ' '
+	}, name)
+
+	words := strings.Fields(name)
+	for i, word := range words {
+		words[i] = singular(word)
+	}
+	return strings.Join(words, " ")
+}
+
+// singular returns a key for word that its singular and plural forms share
+func singular(word string) string {
+	switch {
+	case len(word) <= 3 || unchangedPlurals[word]:
+		return word
+	case strings.HasSuffix(word, "ies"):
+		return strings.TrimSuffix(word, "s") // "utilities" and "utility" both become "utilitie"
+	case strings.HasSuffix(word, "y") && !strings.ContainsRune("aeiou", rune(word[len(word)-2])):
+		return strings.TrimSuffix(word, "y") + "ie"
+	case strings.HasSuffix(word, "sses"), strings.HasSuffix(word, "xes"), strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "shes"):
+		return strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "ss"), strings.HasSuffix(word, "us"), strings.HasSuffix(word, "is"):
+		return word
+	case strings.HasSuffix(word, "s"):
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
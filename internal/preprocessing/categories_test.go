@@ -0,0 +1,82 @@
+package preprocessing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanCategoryName(t *testing.T) {
+	for heading, want := range map[string]string{
+		"APIs":                  "APIs",
+		"DNS":                   "DNS",
+		"News":                  "News",
+		"Tools":                 "Tools",
+		"web development":       "Web development",
+		"🎮 Games 🎮":             "Games",
+		":tv: Streaming":        "Streaming",
+		"1. Getting Started":    "Getting Started",
+		"2.3) Self-hosting":     "Self-hosting",
+		"IV. Linux":             "Linux",
+		"3D Printing":           "3D Printing",
+		"FREE MEDIA":            "Free Media",
+		"vpn providers":         "VPN providers",
+		"► **Useful Sites** ⭐️": "Useful Sites",
+		"C++":                   "C++",
+		"🌐":                     "",
+	} {
+		assert.Equal(t, want, CleanCategoryName(heading), heading)
+	}
+}
+
+func TestCategoryNormalizer(t *testing.T) {
+	normalizer := NewCategoryNormalizer(map[string]string{
+		"Movies & Shows": "Entertainment > Films",
+		"Homelab":        "Self-hosting",
+	})
+
+	tests := []struct {
+		heading  string
+		category string
+		parent   string
+		ok       bool
+	}{
+		{"Movies & TV", "Video", "Media", true},
+		{"📺 Streaming Sites", "Video", "Media", true},
+		{"Torrent Clients", "Torrenting", "Downloading", true},
+		{"Game", "Gaming", "", true},
+		{"Movies and shows", "Films", "Entertainment", true},
+		{"homelabs", "Self-hosting", "", true},
+		{"DNS", "DNS", "", false},
+		{"Utilities", "Utilities", "", false},
+	}
+
+	for _, tt := range tests {
+		category, parent, ok := normalizer.Normalize(tt.heading)
+		assert.Equal(t, tt.category, category, tt.heading)
+		assert.Equal(t, tt.parent, parent, tt.heading)
+		assert.Equal(t, tt.ok, ok, tt.heading)
+	}
+}
+
+func TestValidatorCategories(t *testing.T) {
+	validator := NewDefaultValidator(DefaultProcessingConfig(), &RuleSet{})
+
+	item := validator.Clean(RawItem{
+		URL:            "https://example.com/",
+		Name:           "Example",
+		HeadingContext: []string{"Network", "DNS"},
+		Metadata:       map[string]interface{}{"category": "DNS"},
+	})
+	assert.Equal(t, "DNS", item.Category)
+	assert.Equal(t, "Network", item.Subcategory)
+
+	item = validator.Clean(RawItem{
+		URL:            "https://example.com/",
+		Name:           "Example",
+		HeadingContext: []string{"Free Stuff", "Movies"},
+		Metadata:       map[string]interface{}{"category": "Movies"},
+	})
+	assert.Equal(t, "Video", item.Category)
+	assert.Equal(t, "Media", item.Subcategory)
+}
@@ -1,7 +1,11 @@
 package preprocessing
 
 import (
+	"context"
 	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io/fs"
 	"os"
@@ -10,6 +14,7 @@ import (
 	"sync"
 	"time"
 
+	"freectl/internal/common"
 	"freectl/internal/preprocessing/extractors"
 	"freectl/internal/sources"
 
@@ -21,9 +26,21 @@ type ProcessingEngine struct {
 	config     ProcessingConfig
 	extractors map[string]Extractor
 	validator  ItemValidator
+	rules      *RuleSet
+	ruleHash   string // identifies the rules, category overrides and extraction strategies
 	storage    ProcessedStorage
+	links      *LinkChecker
 	cacheDir   string
 	mu         sync.RWMutex
+
+	// Live status of the sources being processed, see Progress
+	statuses       map[string]*ProcessingStatus
+	statusOrder    []string
+	running        bool
+	runStartedAt   time.Time
+	runCompletedAt *time.Time
+	updated        chan struct{} // closed when the status changes
+	statusMu       sync.Mutex
 }
 
 // NewProcessingEngine creates a new processing engine
@@ -34,9 +51,17 @@ func NewProcessingEngine(cacheDir string, config ProcessingConfig) *ProcessingEn
 		cacheDir:   cacheDir,
 	}
 
+	// Categorise with the user's rules, if they are usable
+	rules, err := LoadRules()
+	if err != nil {
+		log.Warn("Failed to load rules, using the defaults", "error", err)
+		rules = DefaultRules()
+	}
+
 	// Initialize default components
-	engine.validator = NewDefaultValidator(config)
+	engine.SetRules(rules)
 	engine.storage = NewFileStorage(filepath.Join(cacheDir, "processed"))
+	engine.links = NewLinkChecker(DefaultLinkCheckConfig())
 
 	// Convert config to extractors.ProcessingConfig
 	extractorConfig := extractors.ProcessingConfig{
@@ -69,8 +94,71 @@ func (pe *ProcessingEngine) RegisterExtractor(sourceType string, extractor Extra
 	pe.extractors[sourceType] = extractor
 }
 
-// ProcessSource processes a single source into the unified JSON format
+// SetRules replaces the categorisation and tagging rules. Sources processed
+// with other rules, category overrides or extraction strategies are
+// processed again in full.
+func (pe *ProcessingEngine) SetRules(rules *RuleSet) {
+	pe.mu.Lock()
+	defer pe.mu.Unlock()
+	pe.rules = rules
+	pe.ruleHash = processingHash(rules, pe.config.CategoryOverrides, pe.config.ExtractionStrategies)
+	pe.validator = NewDefaultValidator(pe.config, rules)
+}
+
+// SetLinkChecker replaces the checker used for the links of processed items
+func (pe *ProcessingEngine) SetLinkChecker(checker *LinkChecker) {
+	pe.mu.Lock()
+	defer pe.mu.Unlock()
+	pe.links = checker
+}
+
+// processingHash identifies how items are extracted, categorised and tagged
+func processingHash(rules *RuleSet, overrides map[string]string, strategies []string) string {
+	data, _ := json.Marshal(overrides) // map keys are sorted
+	return hashContent([]byte(rules.Hash() + string(data) + strings.ToLower(strings.Join(strategies, ","))))
+}
+
+// ProcessSource processes a single source into the unified JSON format. Only
+// files whose content changed since the source was last processed are
+// extracted again, the items of the others are kept.
 func (pe *ProcessingEngine) ProcessSource(source sources.Source) error {
+	return pe.processSource(source, false)
+}
+
+// ReprocessSource processes every file of a source again, even unchanged ones
+func (pe *ProcessingEngine) ReprocessSource(source sources.Source) error {
+	return pe.processSource(source, true)
+}
+
+// processSource processes a source, recording its progress in the live
+// status
+func (pe *ProcessingEngine) processSource(source sources.Source, full bool) error {
+	pe.setStatus(source.Name, func(status *ProcessingStatus) {
+		*status = ProcessingStatus{
+			SourceName: source.Name,
+			Status:     "processing",
+			Stage:      StageReading,
+			StartedAt:  time.Now(),
+			CheckLinks: pe.config.ValidateURLs,
+		}
+	})
+
+	err := pe.processSourceFiles(source, full)
+	if err != nil {
+		pe.setStatus(source.Name, func(status *ProcessingStatus) {
+			now := time.Now()
+			status.Status = "error"
+			status.Stage = ""
+			status.Error = err.Error()
+			status.CompletedAt = &now
+		})
+	}
+	return err
+}
+
+// processSourceFiles extracts the files of a source, reusing the items of
+// files that haven't changed unless full is set, and saves the merged result
+func (pe *ProcessingEngine) processSourceFiles(source sources.Source, full bool) error {
 	log.Info("Processing source", "name", source.Name, "type", source.Type)
 
 	startTime := time.Now()
@@ -82,11 +170,70 @@ func (pe *ProcessingEngine) ProcessSource(source sources.Source) error {
 	}
 
 	// Read raw content from the source
-	content, err := pe.readSourceContent(source)
+	files, err := pe.readSourceFiles(source)
 	if err != nil {
 		return fmt.Errorf("failed to read source content: %w", err)
 	}
 
+	// Load the previous result to reuse what unchanged files produced
+	var previous *ProcessedSource
+	if pe.storage.Exists(source.Name) {
+		previous, err = pe.storage.Load(source.Name)
+		if err != nil {
+			log.Warn("Failed to load previous processing result, processing all files", "name", source.Name, "error", err)
+		}
+	}
+
+	// Link checks are kept even for items extracted again
+	health := linkHealthByURL(previous)
+	if full {
+		previous = nil
+	}
+
+	previousItems := make(map[string][]ProcessedItem)
+	previousFiles := make(map[string]FileState)
+	// Items categorised with other rules have to be cleaned again
+	if previous != nil && previous.Rules != pe.ruleHash {
+		log.Info("Rules or extraction strategies changed, processing all files", "name", source.Name)
+		previous = nil
+	}
+
+	if previous != nil {
+		for _, item := range previous.Items {
+			previousItems[item.Metadata.FilePath] = append(previousItems[item.Metadata.FilePath], item)
+		}
+		previousFiles = previous.Files
+	}
+
+	hashes := make([]string, len(files))
+	changed := 0
+	for i, file := range files {
+		hashes[i] = hashContent(file.Content)
+		if state, ok := previousFiles[file.Path]; !ok || state.Hash != hashes[i] {
+			changed++
+		}
+	}
+	removed := len(previousFiles) - (len(files) - changed)
+
+	if previous != nil && previous.Files != nil && changed == 0 && removed == 0 {
+		log.Info("Source is unchanged", "name", source.Name, "files", len(files))
+
+		// Links still come due for a check while the files stay the same
+		if pe.config.ValidateURLs && pe.checkItemLinks(context.Background(), source.Name, previous.Items, false, nil) > 0 {
+			pe.setStatus(source.Name, func(status *ProcessingStatus) { status.Stage = StageSaving })
+			if err := pe.storage.Save(*previous); err != nil {
+				return fmt.Errorf("failed to save processed data: %w", err)
+			}
+		}
+		pe.completeStatus(source.Name, previous)
+		return nil
+	}
+
+	pe.setStatus(source.Name, func(status *ProcessingStatus) {
+		status.Stage = StageExtracting
+		status.FilesTotal = len(files)
+	})
+
 	// Create source metadata
 	metadata := SourceMetadata{
 		Name:        source.Name,
@@ -97,50 +244,83 @@ func (pe *ProcessingEngine) ProcessSource(source sources.Source) error {
 		ProcessedAt: time.Now(),
 	}
 
-	// Extract items using the appropriate extractor
-	result, err := extractor.Extract(content, metadata)
-	if err != nil {
-		return fmt.Errorf("extraction failed: %w", err)
+	// Record the commit so line numbers can be linked to upstream
+	if source.Type == sources.SourceTypeGit {
+		if revision, err := sources.GetGitRevision(source.Path); err == nil {
+			metadata.Revision = revision
+		} else {
+			log.Debug("Failed to get source revision", "name", source.Name, "error", err)
+		}
+	}
+
+	processedSource := ProcessedSource{
+		Source: metadata,
+		Files:  make(map[string]FileState, len(files)),
+		Rules:  pe.ruleHash,
 	}
 
-	// Process and validate extracted items
 	var processedItems []ProcessedItem
 	var errors []string
-
-	for _, rawItem := range result.Items {
-		if err := pe.validator.Validate(rawItem); err != nil {
-			errors = append(errors, fmt.Sprintf("validation failed for item %s: %v", rawItem.URL, err))
+	extracted := 0
+
+	for i, file := range files {
+		// Items that were deduplicated against another file have to be
+		// extracted again, since that file may have changed
+		state, ok := previousFiles[file.Path]
+		kept := previousItems[file.Path]
+		if ok && state.Hash == hashes[i] && len(kept) == state.Items {
+			processedItems = append(processedItems, kept...)
+			errors = append(errors, state.Errors...)
+			processedSource.Files[file.Path] = state
+			pe.fileProcessed(source.Name, len(kept))
 			continue
 		}
 
-		processedItem := pe.validator.Clean(rawItem)
-		processedItems = append(processedItems, processedItem)
+		items, fileErrors, err := pe.extractFile(extractor, file, metadata)
+		if err != nil {
+			return fmt.Errorf("extraction failed for %s: %w", file.Path, err)
+		}
+		extracted++
+
+		processedItems = append(processedItems, items...)
+		errors = append(errors, fileErrors...)
+		processedSource.Files[file.Path] = FileState{Hash: hashes[i], Items: len(items), Errors: fileErrors}
+		pe.fileProcessed(source.Name, len(items))
 	}
 
-	// Deduplicate if enabled
+	// Deduplicate across files if enabled
 	if pe.config.DeduplicateItems {
 		processedItems = pe.deduplicateItems(processedItems)
 	}
 
-	// Update metadata
-	metadata.ItemCount = len(processedItems)
-	metadata.Errors = append(result.Errors, errors...)
+	for i := range processedItems {
+		if !processedItems[i].Health.Checked() {
+			processedItems[i].Health = health[common.CanonicalURL(processedItems[i].URL)]
+		}
+	}
 
-	// Create processed source
-	processedSource := ProcessedSource{
-		Source: metadata,
-		Items:  processedItems,
+	if pe.config.ValidateURLs {
+		pe.checkItemLinks(context.Background(), source.Name, processedItems, false, nil)
 	}
 
+	// Update metadata
+	processedSource.Source.ItemCount = len(processedItems)
+	processedSource.Source.Errors = errors
+	processedSource.Items = processedItems
+
 	// Save processed data
+	pe.setStatus(source.Name, func(status *ProcessingStatus) { status.Stage = StageSaving })
 	if err := pe.storage.Save(processedSource); err != nil {
 		return fmt.Errorf("failed to save processed data: %w", err)
 	}
+	pe.completeStatus(source.Name, &processedSource)
 
 	processingTime := time.Since(startTime)
 	log.Info("Source processing completed",
 		"name", source.Name,
 		"items", len(processedItems),
+		"files", len(files),
+		"extracted", extracted,
 		"errors", len(errors),
 		"duration", processingTime,
 	)
@@ -148,22 +328,104 @@ func (pe *ProcessingEngine) ProcessSource(source sources.Source) error {
 	return nil
 }
 
-// ProcessAllSources processes all enabled sources
+// fileProcessed records in the live status that a file of a source is done
+func (pe *ProcessingEngine) fileProcessed(name string, items int) {
+	pe.setStatus(name, func(status *ProcessingStatus) {
+		status.FilesProcessed++
+		status.ItemsProcessed += items
+	})
+}
+
+// completeStatus records in the live status that a source was processed
+func (pe *ProcessingEngine) completeStatus(name string, processed *ProcessedSource) {
+	pe.setStatus(name, func(status *ProcessingStatus) {
+		now := time.Now()
+		status.Status = "completed"
+		status.Stage = ""
+		status.CompletedAt = &now
+		status.FilesTotal = len(processed.Files)
+		status.FilesProcessed = len(processed.Files)
+		status.ItemsProcessed = len(processed.Items)
+		status.ItemsTotal = len(processed.Items)
+		if errors := len(processed.Source.Errors); errors > 0 {
+			status.Status = "completed_with_errors"
+			status.Error = fmt.Sprintf("%d errors occurred", errors)
+		}
+	})
+}
+
+// extractFile extracts, validates and cleans the items of a single file
+func (pe *ProcessingEngine) extractFile(extractor Extractor, file sourceFile, metadata SourceMetadata) ([]ProcessedItem, []string, error) {
+	var result *ExtractionResult
+	var err error
+	if fileExtractor, ok := extractor.(FileExtractor); ok {
+		result, err = fileExtractor.ExtractFile(file.Content, metadata, file.Path)
+	} else {
+		result, err = extractor.Extract(file.Content, metadata)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var items []ProcessedItem
+	errors := result.Errors
+
+	for _, rawItem := range result.Items {
+		if err := pe.validator.Validate(rawItem); err != nil {
+			errors = append(errors, fmt.Sprintf("validation failed for item %s: %v", rawItem.URL, err))
+			continue
+		}
+
+		item := pe.validator.Clean(rawItem)
+		item.Metadata.FilePath = file.Path
+		items = append(items, item)
+	}
+
+	if pe.config.DeduplicateItems {
+		items = pe.deduplicateItems(items)
+	}
+
+	return items, errors, nil
+}
+
+// ProcessAllSources processes all enabled sources. Their progress can be
+// followed with Progress and Updates while it runs.
 func (pe *ProcessingEngine) ProcessAllSources(sourceList []sources.Source) error {
+	return pe.processAllSources(sourceList, false)
+}
+
+// ReprocessAllSources processes every file of all enabled sources again
+func (pe *ProcessingEngine) ReprocessAllSources(sourceList []sources.Source) error {
+	return pe.processAllSources(sourceList, true)
+}
+
+// processAllSources processes the enabled sources, tracking the run in the
+// live status
+func (pe *ProcessingEngine) processAllSources(sourceList []sources.Source, full bool) error {
+	var enabledSources []sources.Source
+	for _, source := range sourceList {
+		if source.Enabled {
+			enabledSources = append(enabledSources, source)
+		}
+	}
+
+	pe.beginRun(enabledSources)
+	defer pe.endRun()
+
 	if !pe.config.ParallelProcessing {
-		return pe.processSequentially(sourceList)
+		return pe.processSequentially(enabledSources, full)
 	}
-	return pe.processInParallel(sourceList)
+	return pe.processInParallel(enabledSources, full)
 }
 
 // processSequentially processes sources one by one
-func (pe *ProcessingEngine) processSequentially(sourceList []sources.Source) error {
+func (pe *ProcessingEngine) processSequentially(sourceList []sources.Source, full bool) error {
 	for _, source := range sourceList {
 		if !source.Enabled {
 			continue
 		}
 
-		if err := pe.ProcessSource(source); err != nil {
+		if err := pe.processSource(source, full); err != nil {
 			log.Error("Failed to process source", "name", source.Name, "error", err)
 			continue
 		}
@@ -172,7 +434,7 @@ func (pe *ProcessingEngine) processSequentially(sourceList []sources.Source) err
 }
 
 // processInParallel processes sources concurrently
-func (pe *ProcessingEngine) processInParallel(sourceList []sources.Source) error {
+func (pe *ProcessingEngine) processInParallel(sourceList []sources.Source, full bool) error {
 	// Filter enabled sources
 	var enabledSources []sources.Source
 	for _, source := range sourceList {
@@ -204,7 +466,7 @@ func (pe *ProcessingEngine) processInParallel(sourceList []sources.Source) error
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 
-			if err := pe.ProcessSource(src); err != nil {
+			if err := pe.processSource(src, full); err != nil {
 				errorChan <- fmt.Errorf("failed to process source %s: %w", src.Name, err)
 			}
 		}(source)
@@ -241,8 +503,14 @@ func (pe *ProcessingEngine) getExtractor(sourceType string) (Extractor, error) {
 	return extractor, nil
 }
 
-// readSourceContent reads the raw content from a source directory
-func (pe *ProcessingEngine) readSourceContent(source sources.Source) ([]byte, error) {
+// sourceFile is a content file of a source
+type sourceFile struct {
+	Path    string // relative to the source directory, with forward slashes
+	Content []byte
+}
+
+// readSourceFiles reads the content files of a source directory
+func (pe *ProcessingEngine) readSourceFiles(source sources.Source) ([]sourceFile, error) {
 	sourcePath := source.Path
 
 	// Check if source directory exists
@@ -255,18 +523,18 @@ func (pe *ProcessingEngine) readSourceContent(source sources.Source) ([]byte, er
 	case sources.SourceTypeGit:
 		return pe.readGitSource(sourcePath)
 	case sources.SourceTypeRSS:
-		return pe.readRSSSource(sourcePath)
+		return readSingleFile(sourcePath, "feed.md")
 	case sources.SourceTypeRedditWiki:
-		return pe.readRedditWikiSource(sourcePath)
+		return readSingleFile(sourcePath, "wiki.md")
 	default:
 		// Default: try to read all markdown files
 		return pe.readMarkdownFiles(sourcePath)
 	}
 }
 
-// readGitSource reads content from a git repository source
-func (pe *ProcessingEngine) readGitSource(sourcePath string) ([]byte, error) {
-	var allContent []byte
+// readGitSource reads the content files of a git repository source
+func (pe *ProcessingEngine) readGitSource(sourcePath string) ([]sourceFile, error) {
+	var files []sourceFile
 
 	err := filepath.WalkDir(sourcePath, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
@@ -288,11 +556,11 @@ func (pe *ProcessingEngine) readGitSource(sourcePath string) ([]byte, error) {
 			return nil // Continue processing other files
 		}
 
-		// Add file separator and path info
-		separator := fmt.Sprintf("\n\n<!-- FILE: %s -->\n\n", path)
-		allContent = append(allContent, []byte(separator)...)
-		allContent = append(allContent, content...)
-		allContent = append(allContent, []byte("\n\n")...)
+		relPath, err := filepath.Rel(sourcePath, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, sourceFile{Path: filepath.ToSlash(relPath), Content: content})
 
 		return nil
 	})
@@ -301,34 +569,116 @@ func (pe *ProcessingEngine) readGitSource(sourcePath string) ([]byte, error) {
 		return nil, fmt.Errorf("failed to walk source directory: %w", err)
 	}
 
-	return allContent, nil
-}
-
-// readRSSSource reads content from an RSS source
-func (pe *ProcessingEngine) readRSSSource(sourcePath string) ([]byte, error) {
-	feedFile := filepath.Join(sourcePath, "feed.md")
-	return os.ReadFile(feedFile)
+	return files, nil
 }
 
-// readRedditWikiSource reads content from a Reddit wiki source
-func (pe *ProcessingEngine) readRedditWikiSource(sourcePath string) ([]byte, error) {
-	wikiFile := filepath.Join(sourcePath, "wiki.md")
-	return os.ReadFile(wikiFile)
+// readSingleFile reads a source stored as a single file, such as an RSS feed
+// or a Reddit wiki page
+func readSingleFile(sourcePath, name string) ([]sourceFile, error) {
+	content, err := os.ReadFile(filepath.Join(sourcePath, name))
+	if err != nil {
+		return nil, err
+	}
+	return []sourceFile{{Path: name, Content: content}}, nil
 }
 
 // readMarkdownFiles reads all markdown files from a directory
-func (pe *ProcessingEngine) readMarkdownFiles(sourcePath string) ([]byte, error) {
+func (pe *ProcessingEngine) readMarkdownFiles(sourcePath string) ([]sourceFile, error) {
 	return pe.readGitSource(sourcePath) // Same logic for now
 }
 
-// deduplicateItems removes duplicate items based on URL
+// CheckLinks checks the links of a processed source and stores their health
+// on its items. Links checked within the checker's MaxAge are skipped unless
+// all is set. progress, when set, is called after each check.
+func (pe *ProcessingEngine) CheckLinks(ctx context.Context, sourceName string, all bool, progress func(link string, health LinkHealth)) (*ProcessedSource, error) {
+	processed, err := pe.storage.Load(sourceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load processed source: %w", err)
+	}
+
+	// Results so far are saved even when the check is cancelled
+	if pe.checkItemLinks(ctx, processed.Source.Name, processed.Items, all, progress) > 0 {
+		if err := pe.storage.Save(*processed); err != nil {
+			return nil, fmt.Errorf("failed to save processed data: %w", err)
+		}
+	}
+
+	return processed, ctx.Err()
+}
+
+// checkItemLinks checks the links of items that are due a check, or of all
+// items when all is set, returning how many were checked
+func (pe *ProcessingEngine) checkItemLinks(ctx context.Context, sourceName string, items []ProcessedItem, all bool, progress func(link string, health LinkHealth)) int {
+	pe.mu.RLock()
+	checker := pe.links
+	pe.mu.RUnlock()
+
+	var links []string
+	for _, item := range items {
+		if all || checker.Stale(item.Health) {
+			links = append(links, item.URL)
+		}
+	}
+	if len(links) == 0 {
+		return 0
+	}
+
+	log.Info("Checking links", "name", sourceName, "links", len(links))
+	pe.setStatus(sourceName, func(status *ProcessingStatus) {
+		status.Stage = StageChecking
+		status.LinksTotal = len(links)
+	})
+	results := checker.CheckAll(ctx, links, func(link string, health LinkHealth) {
+		pe.setStatus(sourceName, func(status *ProcessingStatus) { status.LinksChecked++ })
+		if progress != nil {
+			progress(link, health)
+		}
+	})
+
+	dead := 0
+	for i := range items {
+		if health, ok := results[items[i].URL]; ok {
+			items[i].Health = health
+			if health.Dead() {
+				dead++
+			}
+		}
+	}
+
+	log.Info("Links checked", "name", sourceName, "checked", len(results), "dead", dead)
+	return len(results)
+}
+
+// linkHealthByURL returns the link checks of a processed source's items,
+// keyed by canonical URL
+func linkHealthByURL(processed *ProcessedSource) map[string]LinkHealth {
+	health := make(map[string]LinkHealth)
+	if processed == nil {
+		return health
+	}
+	for _, item := range processed.Items {
+		if item.Health.Checked() {
+			health[common.CanonicalURL(item.URL)] = item.Health
+		}
+	}
+	return health
+}
+
+// hashContent returns the hex encoded SHA-256 of a file's content
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// deduplicateItems removes items linking to the same page as an earlier one
 func (pe *ProcessingEngine) deduplicateItems(items []ProcessedItem) []ProcessedItem {
 	seen := make(map[string]bool)
 	var unique []ProcessedItem
 
 	for _, item := range items {
-		if !seen[item.URL] {
-			seen[item.URL] = true
+		key := common.CanonicalURL(item.URL)
+		if !seen[key] {
+			seen[key] = true
 			unique = append(unique, item)
 		}
 	}
@@ -417,22 +767,29 @@ func isLinkHeavyReadme(path string) bool {
 	return linkLines >= 10 && float64(linkLines)/float64(totalLines) >= 0.2
 }
 
-// GetProcessingStatus returns the current processing status for all sources
+// GetProcessingStatus returns the current processing status for all sources,
+// live for those this engine is processing or has processed and from the
+// stored results for the others
 func (pe *ProcessingEngine) GetProcessingStatus() ([]ProcessingStatus, error) {
 	processedSources, err := pe.storage.List()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list processed sources: %w", err)
 	}
 
-	var statuses []ProcessingStatus
+	statuses := pe.Progress().Sources
+	live := make(map[string]bool, len(statuses))
+	for _, status := range statuses {
+		live[status.SourceName] = true
+	}
+
 	for _, sourceName := range processedSources {
 		processed, err := pe.storage.Load(sourceName)
-		if err != nil {
+		if err != nil || live[processed.Source.Name] {
 			continue
 		}
 
 		status := ProcessingStatus{
-			SourceName:     sourceName,
+			SourceName:     processed.Source.Name,
 			Status:         "completed",
 			StartedAt:      processed.Source.ProcessedAt,
 			CompletedAt:    &processed.Source.ProcessedAt,
@@ -451,7 +808,9 @@ func (pe *ProcessingEngine) GetProcessingStatus() ([]ProcessingStatus, error) {
 	return statuses, nil
 }
 
-// NeedsProcessing checks if a source needs to be processed or reprocessed
+// NeedsProcessing checks if a source needs to be processed or reprocessed,
+// which is when any of its files was added, removed or changed, or the rules
+// changed
 func (pe *ProcessingEngine) NeedsProcessing(source sources.Source) bool {
 	if !pe.storage.Exists(source.Name) {
 		return true
@@ -462,13 +821,24 @@ func (pe *ProcessingEngine) NeedsProcessing(source sources.Source) bool {
 		return true
 	}
 
-	// Check if source was updated after last processing
-	sourceInfo, err := os.Stat(source.Path)
-	if err != nil {
+	// Sources processed before file hashes were recorded, or with other rules
+	if processed.Files == nil || processed.Rules != pe.ruleHash {
 		return true
 	}
 
-	return sourceInfo.ModTime().After(processed.Source.ProcessedAt)
+	files, err := pe.readSourceFiles(source)
+	if err != nil || len(files) != len(processed.Files) {
+		return true
+	}
+
+	for _, file := range files {
+		state, ok := processed.Files[file.Path]
+		if !ok || state.Hash != hashContent(file.Content) {
+			return true
+		}
+	}
+
+	return false
 }
 
 // stubExtractor is a placeholder extractor for unimplemented types
@@ -0,0 +1,118 @@
+package preprocessing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"freectl/internal/sources"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingExtractor counts which files were extracted
+type countingExtractor struct {
+	Extractor
+	extracted []string
+}
+
+func (ce *countingExtractor) ExtractFile(content []byte, source SourceMetadata, filePath string) (*ExtractionResult, error) {
+	ce.extracted = append(ce.extracted, filePath)
+	return ce.Extractor.(FileExtractor).ExtractFile(content, source, filePath)
+}
+
+func TestProcessSourceIncremental(t *testing.T) {
+	cacheDir := t.TempDir()
+	sourceDir := filepath.Join(cacheDir, "demo")
+	require.NoError(t, os.MkdirAll(filepath.Join(sourceDir, "docs"), 0755))
+
+	writeFile := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(sourceDir, name), []byte(content), 0644))
+	}
+	writeFile("docs/wikis.md", "# Wikis\n\n- [Wiki.js](https://js.wiki/) - A modern wiki\n- [BookStack](https://bookstackapp.com/) - Wiki for documentation\n")
+	writeFile("torrents.md", "# Torrents\n\n- [qBittorrent](https://qbittorrent.org/) - Torrent client\n- [Wiki.js](https://js.wiki/) - Listed twice\n")
+
+	engine := NewProcessingEngine(cacheDir, DefaultProcessingConfig())
+	extractor := &countingExtractor{Extractor: engine.extractors["git"]}
+	engine.RegisterExtractor("git", extractor)
+
+	source := sources.Source{Name: "demo", Path: sourceDir, Type: sources.SourceTypeGit, Enabled: true}
+	assert.True(t, engine.NeedsProcessing(source))
+	require.NoError(t, engine.ProcessSource(source))
+	assert.ElementsMatch(t, []string{"docs/wikis.md", "torrents.md"}, extractor.extracted)
+
+	processed, err := engine.storage.Load("demo")
+	require.NoError(t, err)
+	assert.Len(t, processed.Items, 3)
+	assert.Len(t, processed.Files, 2)
+	assert.False(t, engine.NeedsProcessing(source))
+	for _, item := range processed.Items {
+		if item.URL == "https://bookstackapp.com/" {
+			assert.Equal(t, "docs/wikis.md", item.Metadata.FilePath)
+			assert.Equal(t, 4, item.Metadata.LineNumber)
+		}
+	}
+
+	// Nothing changed
+	extractor.extracted = nil
+	require.NoError(t, engine.ProcessSource(source))
+	assert.Empty(t, extractor.extracted)
+
+	// Only the changed file is extracted again
+	writeFile("docs/wikis.md", "# Wikis\n\n- [BookStack](https://bookstackapp.com/) - Wiki for documentation\n- [DokuWiki](https://dokuwiki.org/) - Simple wiki\n")
+	assert.True(t, engine.NeedsProcessing(source))
+	require.NoError(t, engine.ProcessSource(source))
+
+	// torrents.md lost Wiki.js to deduplication, so it is extracted
+	// again now that docs/wikis.md no longer lists it
+	assert.ElementsMatch(t, []string{"docs/wikis.md", "torrents.md"}, extractor.extracted)
+
+	processed, err = engine.storage.Load("demo")
+	require.NoError(t, err)
+	urls := make(map[string]string)
+	for _, item := range processed.Items {
+		urls[item.URL] = item.Metadata.FilePath
+	}
+	assert.Equal(t, map[string]string{
+		"https://bookstackapp.com/": "docs/wikis.md",
+		"https://dokuwiki.org/":     "docs/wikis.md",
+		"https://qbittorrent.org/":  "torrents.md",
+		"https://js.wiki/":          "torrents.md",
+	}, urls)
+
+	// Unchanged files that lost nothing to deduplication are kept
+	extractor.extracted = nil
+	writeFile("docs/wikis.md", "# Wikis\n\n- [DokuWiki](https://dokuwiki.org/) - Simple wiki\n")
+	require.NoError(t, engine.ProcessSource(source))
+	assert.Equal(t, []string{"docs/wikis.md"}, extractor.extracted)
+
+	// Removed files drop their items
+	require.NoError(t, os.Remove(filepath.Join(sourceDir, "torrents.md")))
+	assert.True(t, engine.NeedsProcessing(source))
+	require.NoError(t, engine.ProcessSource(source))
+	processed, err = engine.storage.Load("demo")
+	require.NoError(t, err)
+	require.Len(t, processed.Items, 1)
+	assert.Equal(t, "https://dokuwiki.org/", processed.Items[0].URL)
+
+	// Reprocessing extracts everything
+	extractor.extracted = nil
+	require.NoError(t, engine.ReprocessSource(source))
+	assert.Equal(t, []string{"docs/wikis.md"}, extractor.extracted)
+}
+
+func TestDeduplicateItemsComparesCanonicalURLs(t *testing.T) {
+	engine := &ProcessingEngine{}
+	items := engine.deduplicateItems([]ProcessedItem{
+		{URL: "https://github.com/neovim/neovim", Name: "Neovim"},
+		{URL: "https://github.com/Neovim/neovim.git", Name: "Neovim (git)"},
+		{URL: "http://www.js.wiki/?utm_source=awesome", Name: "Wiki.js"},
+		{URL: "https://js.wiki/", Name: "Wiki.js again"},
+	})
+
+	require.Len(t, items, 2)
+	assert.Equal(t, "https://github.com/neovim/neovim", items[0].URL)
+	// The first URL is kept as written
+	assert.Equal(t, "http://www.js.wiki/?utm_source=awesome", items[1].URL)
+}
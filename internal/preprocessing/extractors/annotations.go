@@ -0,0 +1,461 @@
+package extractors
+
+import (
+	"regexp"
+	"strings"
+
+	"freectl/internal/common"
+)
+
+// Pricing values recognised from annotations
+const (
+	PricingFree      = "free"
+	PricingFreemium  = "freemium"
+	PricingFreeTrial = "free trial"
+	PricingPaid      = "paid"
+)
+
+// Annotations are markers next to the main link of a list entry, such as
+// badges, "(Paid)", platform emojis or a "[Source Code](...)" link. They
+// describe the entry rather than being entries of their own.
+type Annotations struct {
+	OpenSource    bool
+	SourceCodeURL string
+	DemoURL       string
+	Pricing       string
+	Platforms     []string
+	Featured      bool // starred by the list, such as fmhy's ⭐
+	Stars         int
+}
+
+// Roles of the links in an entry
+const (
+	linkMain   = ""
+	linkBadge  = "badge"
+	linkSource = "source"
+	linkDemo   = "demo"
+)
+
+var (
+	// entryLinkPattern matches [text](url) and badge links [![alt](image)](url)
+	entryLinkPattern = regexp.MustCompile(`\[(!\[[^\]]*\]\([^)]*\)|[^\[\]]*)\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+
+	// entryImagePattern matches inline and reference images ![alt](image)
+	// and ![alt][ref]
+	entryImagePattern = regexp.MustCompile(`!\[([^\]]*)\](?:\(([^)\s]+)[^)]*\)|\[[^\]]*\])`)
+
+	// entryMarkerPattern matches parenthesised markers such as "(Paid)"
+	entryMarkerPattern = regexp.MustCompile(`\(([^()]{2,30})\)`)
+
+	// emptyGroupPattern matches brackets left empty by removed annotations
+	emptyGroupPattern = regexp.MustCompile(`[(\[][\s,;|/]*[)\]]`)
+
+	// entryStarsPattern matches a star count such as "⭐ 1.2k"
+	entryStarsPattern = regexp.MustCompile(`[⭐★]\s*(\d[\d.,]*[kKmM]?)`)
+)
+
+// sourceLinkLabels are link texts pointing to an entry's source code
+var sourceLinkLabels = map[string]bool{
+	"source code": true, "source": true, "code": true, "src": true,
+	"repo": true, "repository": true, "git": true,
+	"github": true, "gitlab": true, "codeberg": true,
+}
+
+// demoLinkLabels are link texts pointing to a demo of an entry
+var demoLinkLabels = map[string]bool{
+	"demo": true, "live demo": true, "online demo": true, "try it": true,
+	"try it online": true, "try online": true, "playground": true,
+}
+
+// pricingLabels are pricing markers and their pricing. Of those matching a
+// marker the longest wins, and the first listed among equally long ones.
+var pricingLabels = []struct{ label, pricing string }{
+	{"free", PricingFree},
+	{"freeware", PricingFree},
+	{"freemium", PricingFreemium},
+	{"free trial", PricingFreeTrial},
+	{"trial", PricingFreeTrial},
+	{"paid", PricingPaid},
+	{"commercial", PricingPaid},
+	{"subscription", PricingPaid},
+	{"one-time purchase", PricingPaid},
+	{"paid with free tier", PricingFreemium},
+}
+
+// platformLabels maps platform markers to their platform
+var platformLabels = map[string]string{
+	"web":     "Web",
+	"linux":   "Linux",
+	"windows": "Windows",
+	"win":     "Windows",
+	"macos":   "macOS",
+	"mac":     "macOS",
+	"osx":     "macOS",
+	"android": "Android",
+	"ios":     "iOS",
+}
+
+// openSourceLabels are markers of open-source entries
+var openSourceLabels = []string{
+	"open source", "open-source", "oss", "foss",
+	"libre", "open source software", "open-source software",
+}
+
+// emojiMarkers are emojis and the annotation each stands for. They are
+// applied in this order, so platforms are listed in it and the last
+// pricing emoji in an entry wins.
+var emojiMarkers = []struct {
+	emoji string
+	apply func(*Annotations)
+}{
+	{"🌐", func(a *Annotations) { a.addPlatform("Web") }},
+	{"🐧", func(a *Annotations) { a.addPlatform("Linux") }},
+	{"🍎", func(a *Annotations) { a.addPlatform("macOS") }},
+	{"🪟", func(a *Annotations) { a.addPlatform("Windows") }},
+	{"🤖", func(a *Annotations) { a.addPlatform("Android") }},
+	{"📱", func(a *Annotations) { a.addPlatform("Mobile") }},
+	{"💲", func(a *Annotations) { a.Pricing = PricingPaid }},
+	{"💰", func(a *Annotations) { a.Pricing = PricingPaid }},
+	{"🆓", func(a *Annotations) { a.Pricing = PricingFree }},
+	{"⭐", func(a *Annotations) { a.Featured = true }},
+	{"🌟", func(a *Annotations) { a.Featured = true }},
+	{"🔓", func(a *Annotations) { a.OpenSource = true }},
+}
+
+// codeHosts are hosts whose links are usually to source code
+var codeHosts = []string{"github.com", "gitlab.com", "codeberg.org", "bitbucket.org", "sr.ht", "git.sr.ht", "sourceforge.net"}
+
+// badgeHosts serve badge images
+var badgeHosts = []string{"img.shields.io", "shields.io", "badgen.net", "badge.fury.io", "travis-ci.org", "api.netlify.com"}
+
+// entryMainLink returns the text and URL of the first link an entry is
+// about, skipping badges and other annotation links
+func entryMainLink(entry string) (string, string) {
+	links := parseEntryLinks(entry)
+	if len(links) == 0 {
+		return common.ExtractMarkdownLink(entry)
+	}
+	for _, link := range links {
+		if link.role == linkMain {
+			return link.text, link.url
+		}
+	}
+	return "", ""
+}
+
+// entryURL returns the first URL in an entry that isn't an annotation
+func entryURL(entry string) string {
+	rest := entry
+	for {
+		url := common.ExtractURL(rest)
+		if url == "" || !IsAnnotationLink(entry, url) {
+			return url
+		}
+		rest = rest[strings.Index(rest, url)+len(url):]
+	}
+}
+
+// entryLink is a markdown link in an entry
+type entryLink struct {
+	text, url  string
+	start, end int
+	role       string
+}
+
+// parseEntryLinks returns the links in an entry and what each is for. When
+// no link is plainly the entry's own, the first one that isn't a badge is.
+func parseEntryLinks(entry string) []entryLink {
+	var links []entryLink
+	for _, m := range entryLinkPattern.FindAllStringSubmatchIndex(entry, -1) {
+		// The link text of an image, not a link
+		if m[0] > 0 && entry[m[0]-1] == '!' {
+			continue
+		}
+		link := entryLink{text: entry[m[2]:m[3]], url: entry[m[4]:m[5]], start: m[0], end: m[1]}
+		link.role = linkRole(link.text, link.url)
+		links = append(links, link)
+	}
+
+	for _, link := range links {
+		if link.role == linkMain {
+			return links
+		}
+	}
+	for i := range links {
+		if links[i].role != linkBadge {
+			links[i].role = linkMain
+			break
+		}
+	}
+	return links
+}
+
+// linkRole returns what a link in an entry is for from its text and URL
+func linkRole(text, url string) string {
+	label := strings.ToLower(strings.Trim(strings.TrimSpace(text), "*_`"))
+	switch {
+	case strings.HasPrefix(label, "![") || IsBadgeURL(url):
+		return linkBadge
+	case sourceLinkLabels[label]:
+		return linkSource
+	case demoLinkLabels[label]:
+		return linkDemo
+	}
+	return linkMain
+}
+
+// IsBadgeURL reports whether url is a badge image, such as from shields.io
+func IsBadgeURL(url string) bool {
+	return hasHost(url, badgeHosts)
+}
+
+// hasHost reports whether url is on one of hosts or their subdomains
+func hasHost(url string, hosts []string) bool {
+	domain := strings.ToLower(common.ExtractDomain(url))
+	for _, host := range hosts {
+		if domain == host || strings.HasSuffix(domain, "."+host) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAnnotationLink reports whether the link to url in an entry's markdown is
+// an annotation, such as a badge or a source code link, rather than what
+// the entry is about
+func IsAnnotationLink(entry, url string) bool {
+	for _, link := range parseEntryLinks(entry) {
+		if link.url == url {
+			return link.role != linkMain
+		}
+	}
+	return IsBadgeURL(url)
+}
+
+// EntryAnnotations returns the annotations of the link to url in an entry's
+// markdown. Annotations belong to the main link they follow, and those
+// before the first main link belong to it. When the entry has no link to
+// url, such as a bare URL, every annotation in the entry is returned.
+func EntryAnnotations(entry, url string) Annotations {
+	links := parseEntryLinks(entry)
+
+	start, end := 0, len(entry)
+	first := true
+	for i, link := range links {
+		if link.role != linkMain || link.url != url {
+			if link.role == linkMain {
+				first = false
+			}
+			continue
+		}
+		if !first {
+			start = link.start
+		}
+		for _, next := range links[i+1:] {
+			if next.role == linkMain {
+				end = next.start
+				break
+			}
+		}
+		break
+	}
+
+	return annotationsIn(entry[start:end], url)
+}
+
+// annotationsIn collects the annotations in a segment of an entry that
+// belong to the main link url
+func annotationsIn(segment, url string) Annotations {
+	var annotations Annotations
+
+	// Blank out links so their text isn't read as markers
+	text := []byte(segment)
+	for _, link := range parseEntryLinks(segment) {
+		switch link.role {
+		case linkBadge:
+			annotations.applyBadge(link.text, link.url, url)
+		case linkSource:
+			if link.url != url && annotations.SourceCodeURL == "" {
+				annotations.SourceCodeURL = link.url
+			}
+			annotations.OpenSource = true
+		case linkDemo:
+			if annotations.DemoURL == "" {
+				annotations.DemoURL = link.url
+			}
+		}
+		for i := link.start; i < link.end; i++ {
+			text[i] = ' '
+		}
+	}
+
+	for _, m := range entryImagePattern.FindAllSubmatchIndex(text, -1) {
+		annotations.applyLabel(string(text[m[2]:m[3]]), false)
+		for i := m[0]; i < m[1]; i++ {
+			text[i] = ' '
+		}
+	}
+
+	for _, m := range entryStarsPattern.FindAllSubmatch(text, -1) {
+		if stars := parseStars(string(m[1])); stars > annotations.Stars {
+			annotations.Stars = stars
+		}
+	}
+	text = entryStarsPattern.ReplaceAll(text, nil)
+
+	for _, m := range entryMarkerPattern.FindAllSubmatch(text, -1) {
+		annotations.applyLabel(string(m[1]), true)
+	}
+
+	for _, marker := range emojiMarkers {
+		if strings.Contains(string(text), marker.emoji) {
+			marker.apply(&annotations)
+		}
+	}
+
+	return annotations
+}
+
+// applyBadge records what a badge linking to target says about the entry
+// whose main link is url
+func (a *Annotations) applyBadge(text, target, url string) {
+	alt := text
+	if m := entryImagePattern.FindStringSubmatch(text); m != nil {
+		alt = m[1]
+	}
+	a.applyLabel(alt, false)
+
+	label := strings.ToLower(alt)
+	switch {
+	case strings.Contains(label, "demo"):
+		a.DemoURL = target
+	case strings.Contains(label, "license"):
+		a.OpenSource = true
+	}
+	if target != url && a.SourceCodeURL == "" && hasHost(target, codeHosts) {
+		a.SourceCodeURL = target
+	}
+}
+
+// applyLabel records what a marker says about the entry. Exact markers, such
+// as "(Paid)", must match a known label, while image alt texts only have to
+// contain one.
+func (a *Annotations) applyLabel(label string, exact bool) {
+	label = strings.ToLower(strings.TrimSpace(label))
+	if label == "" {
+		return
+	}
+
+	matches := func(known string) bool {
+		if exact {
+			return label == known
+		}
+		return label == known || (len(known) > 3 && strings.Contains(label, known))
+	}
+
+	for _, known := range openSourceLabels {
+		if matches(known) {
+			a.OpenSource = true
+		}
+	}
+
+	// The longest match wins, so "free trial" isn't read as "free"
+	longest := 0
+	for _, known := range pricingLabels {
+		if matches(known.label) && len(known.label) > longest {
+			a.Pricing, longest = known.pricing, len(known.label)
+		}
+	}
+
+	// A marker may list several platforms, such as "(Windows, Linux)"
+	if exact {
+		for _, value := range splitList(label) {
+			if platform, ok := platformLabels[strings.TrimSpace(value)]; ok {
+				a.addPlatform(platform)
+			}
+		}
+	} else if platform, ok := platformLabels[label]; ok {
+		a.addPlatform(platform)
+	}
+}
+
+// addPlatform adds a platform unless it is already listed
+func (a *Annotations) addPlatform(platform string) {
+	for _, existing := range a.Platforms {
+		if strings.EqualFold(existing, platform) {
+			return
+		}
+	}
+	a.Platforms = append(a.Platforms, platform)
+}
+
+// Apply stores the annotations in an item's metadata, keeping attributes the
+// item already has
+func (a Annotations) Apply(metadata map[string]interface{}) {
+	if a.OpenSource {
+		metadata["open_source"] = true
+	}
+	if a.SourceCodeURL != "" {
+		metadata["source_code_url"] = a.SourceCodeURL
+	}
+	if a.DemoURL != "" {
+		metadata["demo_url"] = a.DemoURL
+	}
+	if a.Pricing != "" {
+		metadata["pricing"] = a.Pricing
+	}
+	if a.Featured {
+		metadata["featured"] = true
+	}
+	if _, ok := metadata["stars"]; !ok && a.Stars > 0 {
+		metadata["stars"] = a.Stars
+	}
+	if len(a.Platforms) > 0 {
+		platforms, _ := metadata["platforms"].([]string)
+		for _, platform := range a.Platforms {
+			known := false
+			for _, existing := range platforms {
+				known = known || strings.EqualFold(existing, platform)
+			}
+			if !known {
+				platforms = append(platforms, platform)
+			}
+		}
+		metadata["platforms"] = platforms
+	}
+}
+
+// StripAnnotations removes badges, annotation links and markers from an
+// entry, leaving its main links and description
+func StripAnnotations(entry string) string {
+	var b strings.Builder
+	last := 0
+	for _, link := range parseEntryLinks(entry) {
+		if link.role == linkMain {
+			continue
+		}
+		b.WriteString(entry[last:link.start])
+		last = link.end
+	}
+	b.WriteString(entry[last:])
+	stripped := entryImagePattern.ReplaceAllString(b.String(), "")
+	stripped = entryStarsPattern.ReplaceAllString(stripped, "")
+
+	stripped = entryMarkerPattern.ReplaceAllStringFunc(stripped, func(marker string) string {
+		var annotations Annotations
+		annotations.applyLabel(strings.Trim(marker, "()"), true)
+		if annotations.OpenSource || annotations.Pricing != "" || len(annotations.Platforms) > 0 {
+			return ""
+		}
+		return marker
+	})
+
+	for _, marker := range emojiMarkers {
+		stripped = strings.ReplaceAll(stripped, marker.emoji, "")
+	}
+
+	// Brackets and separators left behind by removed annotations
+	stripped = emptyGroupPattern.ReplaceAllString(stripped, "")
+	stripped = strings.Join(strings.Fields(stripped), " ")
+	return strings.TrimRight(stripped, " -|,/")
+}
@@ -0,0 +1,89 @@
+package extractors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const annotated = `# Software
+
+## Wikis
+
+- [Wiki.js](https://js.wiki/) - A modern wiki. ([Demo](https://demo.js.wiki/), [Source Code](https://github.com/requarks/wiki)) ` + "`AGPL-3.0`" + ` 🌐
+- [![GitHub stars](https://img.shields.io/github/stars/BookStackApp/BookStack)](https://github.com/BookStackApp/BookStack) [BookStack](https://www.bookstackapp.com/) - Wiki for documentation ![Open-Source Software][oss]
+- ⭐ [Notion](https://notion.so/) - All-in-one workspace (Freemium) (Windows, macOS)
+`
+
+func TestEntryAnnotations(t *testing.T) {
+	entry := "[Wiki.js](https://js.wiki/) - A wiki ([Demo](https://demo.js.wiki/)) (Paid) 🐧 [Other](https://other.com/) (Free)"
+
+	wiki := EntryAnnotations(entry, "https://js.wiki/")
+	assert.Equal(t, "https://demo.js.wiki/", wiki.DemoURL)
+	assert.Equal(t, PricingPaid, wiki.Pricing)
+	assert.Equal(t, []string{"Linux"}, wiki.Platforms)
+
+	other := EntryAnnotations(entry, "https://other.com/")
+	assert.Equal(t, PricingFree, other.Pricing)
+	assert.Empty(t, other.DemoURL)
+
+	assert.True(t, IsAnnotationLink(entry, "https://demo.js.wiki/"))
+	assert.False(t, IsAnnotationLink(entry, "https://js.wiki/"))
+
+	// A lone link is the entry, whatever it's called
+	assert.False(t, IsAnnotationLink("[GitHub](https://github.com/) - Code hosting", "https://github.com/"))
+}
+
+func TestEntryAnnotationsOrder(t *testing.T) {
+	// Emojis are applied in a fixed order, whatever the order in the entry
+	for range 20 {
+		annotations := EntryAnnotations("[Tool](https://tool.com/) 🆓 🐧 💲 🌐 🤖", "https://tool.com/")
+		assert.Equal(t, []string{"Web", "Linux", "Android"}, annotations.Platforms)
+		assert.Equal(t, PricingFree, annotations.Pricing)
+	}
+}
+
+func TestStripAnnotations(t *testing.T) {
+	assert.Equal(t,
+		"[Notion](https://notion.so/) - All-in-one workspace",
+		StripAnnotations("⭐ [Notion](https://notion.so/) - All-in-one workspace (Freemium) 🌐 [![stars](https://img.shields.io/x)](https://github.com/x)"))
+	assert.Equal(t,
+		"[Joplin](https://joplinapp.org/) - Note taking app",
+		StripAnnotations("[Joplin](https://joplinapp.org/) - Note taking app ([Source Code](https://github.com/laurent22/joplin), [Demo](https://demo.joplin.org/))"))
+	assert.Equal(t, "Plain (not a marker)", StripAnnotations("Plain (not a marker)"))
+}
+
+func TestMarkdownExtractorAttachesAnnotations(t *testing.T) {
+	extractor := NewMarkdownExtractor(ProcessingConfig{})
+	result, err := extractor.ExtractFile([]byte(annotated), SourceMetadata{Name: "software"}, "software.md")
+	require.NoError(t, err)
+
+	items := make(map[string]RawItem)
+	for _, item := range result.Items {
+		items[item.URL] = item
+	}
+
+	// Badges, demos and source code links aren't items of their own
+	assert.Len(t, items, 3)
+	assert.NotContains(t, items, "https://demo.js.wiki/")
+	assert.NotContains(t, items, "https://github.com/requarks/wiki")
+	assert.NotContains(t, items, "https://github.com/BookStackApp/BookStack")
+
+	wiki := items["https://js.wiki/"]
+	assert.Equal(t, "Wiki.js", wiki.Name)
+	assert.Equal(t, "https://demo.js.wiki/", wiki.Metadata["demo_url"])
+	assert.Equal(t, "https://github.com/requarks/wiki", wiki.Metadata["source_code_url"])
+	assert.Equal(t, true, wiki.Metadata["open_source"])
+	assert.Equal(t, []string{"Web"}, wiki.Metadata["platforms"])
+
+	bookstack := items["https://www.bookstackapp.com/"]
+	assert.Equal(t, "BookStack", bookstack.Name)
+	assert.Equal(t, "https://github.com/BookStackApp/BookStack", bookstack.Metadata["source_code_url"])
+	assert.Equal(t, true, bookstack.Metadata["open_source"])
+
+	notion := items["https://notion.so/"]
+	assert.Equal(t, true, notion.Metadata["featured"])
+	assert.Equal(t, PricingFreemium, notion.Metadata["pricing"])
+	assert.Equal(t, []string{"Windows", "macOS"}, notion.Metadata["platforms"])
+}
@@ -25,6 +25,7 @@ type SourceMetadata struct {
 	Type        string    `json:"type"`
 	LastUpdated time.Time `json:"last_updated"`
 	Version     string    `json:"version"`
+	Revision    string    `json:"revision,omitempty"` // commit of git sources when processed
 	ProcessedAt time.Time `json:"processed_at"`
 	ItemCount   int       `json:"item_count"`
 	Errors      []string  `json:"errors,omitempty"`
@@ -73,6 +74,16 @@ type Extractor interface {
 	Name() string
 }
 
+// FileExtractor is implemented by extractors that can process a source one
+// file at a time, so unchanged files don't have to be extracted again
+type FileExtractor interface {
+	Extractor
+
+	// ExtractFile processes the content of a single file, whose path is
+	// relative to the source directory
+	ExtractFile(content []byte, source SourceMetadata, filePath string) (*ExtractionResult, error)
+}
+
 // Strategy represents a specific extraction strategy
 type Strategy interface {
 	// Extract items using this strategy
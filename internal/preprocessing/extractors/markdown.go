@@ -1,7 +1,9 @@
 package extractors
 
 import (
+	"bytes"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -22,7 +24,9 @@ type MarkdownExtractor struct {
 	markdown   goldmark.Markdown
 }
 
-// NewMarkdownExtractor creates a new markdown extractor
+// NewMarkdownExtractor creates a new markdown extractor. It runs the
+// strategies named in config.ExtractionStrategies, or all of them when none
+// are named.
 func NewMarkdownExtractor(config ProcessingConfig) *MarkdownExtractor {
 	// Initialize goldmark with GitHub Flavored Markdown
 	md := goldmark.New(
@@ -37,36 +41,85 @@ func NewMarkdownExtractor(config ProcessingConfig) *MarkdownExtractor {
 		markdown: md,
 	}
 
-	// Initialize strategies in order of preference
-	extractor.strategies = []Strategy{
+	extractor.strategies = selectStrategies(newStrategies(md), config.ExtractionStrategies)
+
+	return extractor
+}
+
+// newStrategies returns every strategy in order of preference
+func newStrategies(md goldmark.Markdown) []Strategy {
+	return []Strategy{
+		NewTableMarkdownStrategy(md),
 		NewStructuredMarkdownStrategy(md),
 		NewRegexMarkdownStrategy(),
 		NewSimpleMarkdownStrategy(),
 	}
+}
 
-	return extractor
+// CheckStrategies returns an error if any of names isn't an extraction
+// strategy, since extracting without the intended strategies finds nothing
+// or the wrong items
+func CheckStrategies(names []string) error {
+	var known []string
+	for _, strategy := range newStrategies(goldmark.New()) {
+		known = append(known, strategy.Name())
+	}
+	for _, name := range names {
+		if !slices.ContainsFunc(known, func(k string) bool { return strings.EqualFold(k, name) }) {
+			return fmt.Errorf("unknown extraction strategy '%s', expected one of: %s", name, strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
+
+// selectStrategies returns the strategies with the given names, in the order
+// they are named. Unknown names are skipped with a warning.
+func selectStrategies(available []Strategy, names []string) []Strategy {
+	if len(names) == 0 {
+		return available
+	}
+
+	var selected []Strategy
+	for _, name := range names {
+		index := slices.IndexFunc(available, func(s Strategy) bool { return strings.EqualFold(s.Name(), name) })
+		if index == -1 {
+			log.Warn("Unknown extraction strategy", "strategy", name)
+			continue
+		}
+		if !slices.Contains(selected, available[index]) {
+			selected = append(selected, available[index])
+		}
+	}
+	return selected
 }
 
 // Extract processes markdown content and returns extracted items
 func (me *MarkdownExtractor) Extract(content []byte, source SourceMetadata) (*ExtractionResult, error) {
+	return me.ExtractFile(content, source, "")
+}
+
+// ExtractFile processes the markdown content of a single file and returns
+// extracted items, recording the file they came from. Every strategy that
+// can handle the content is run, and when several find the same URL the
+// variant with the highest confidence is kept.
+func (me *MarkdownExtractor) ExtractFile(content []byte, source SourceMetadata, filePath string) (*ExtractionResult, error) {
 	startTime := time.Now()
 
 	context := ExtractionContext{
-		Source: source,
-		Config: me.config,
+		Source:   source,
+		FilePath: filePath,
+		Config:   me.config,
 	}
 
 	var allItems []RawItem
 	var errors []string
-	var strategyUsed string
 
-	// Try each strategy until one succeeds
 	for _, strategy := range me.strategies {
 		if !strategy.CanHandle(content) {
 			continue
 		}
 
-		log.Debug("Trying extraction strategy", "strategy", strategy.Name(), "source", source.Name)
+		log.Debug("Running extraction strategy", "strategy", strategy.Name(), "source", source.Name, "file", filePath)
 
 		items, err := strategy.Extract(content, context)
 		if err != nil {
@@ -74,37 +127,38 @@ func (me *MarkdownExtractor) Extract(content []byte, source SourceMetadata) (*Ex
 			continue
 		}
 
-		if len(items) > 0 {
-			allItems = items
-			strategyUsed = strategy.Name()
-			break
+		for _, item := range items {
+			if item.Metadata == nil {
+				item.Metadata = make(map[string]interface{})
+			}
+			item.Metadata["strategy"] = strategy.Name()
+			allItems = append(allItems, item)
 		}
 	}
 
-	// If no strategy produced results, combine all attempts
-	if len(allItems) == 0 {
-		log.Warn("All strategies failed or produced no results", "source", source.Name)
+	merged := MergeItems(allItems)
+	if len(merged) == 0 {
+		log.Debug("No strategy found any links", "source", source.Name, "file", filePath)
+	}
 
-		// Try simple strategy as last resort
-		simpleStrategy := NewSimpleMarkdownStrategy()
-		items, err := simpleStrategy.Extract(content, context)
-		if err != nil {
-			errors = append(errors, fmt.Sprintf("fallback strategy failed: %v", err))
-		} else {
-			allItems = items
-			strategyUsed = simpleStrategy.Name()
+	// Record which strategies the kept items came from
+	var strategiesUsed []string
+	for _, item := range merged {
+		if name, _ := item.Metadata["strategy"].(string); !slices.Contains(strategiesUsed, name) {
+			strategiesUsed = append(strategiesUsed, name)
 		}
 	}
 
 	stats := ExtractionStats{
 		TotalItems:     len(allItems),
-		ValidItems:     len(allItems),
+		ValidItems:     len(merged),
+		DuplicateItems: len(allItems) - len(merged),
 		ProcessingTime: time.Since(startTime),
-		ExtractorUsed:  strategyUsed,
+		ExtractorUsed:  strings.Join(strategiesUsed, ","),
 	}
 
 	return &ExtractionResult{
-		Items:  allItems,
+		Items:  merged,
 		Errors: errors,
 		Stats:  stats,
 	}, nil
@@ -159,15 +213,7 @@ func (sms *StructuredMarkdownStrategy) Extract(content []byte, context Extractio
 	// Track heading hierarchy
 	headings := make(map[int]string)
 	var currentContext string
-	var currentFile string
-
-	// Extract current file from content if it has file markers
-	lines := strings.Split(string(content), "\n")
-	for _, line := range lines {
-		if strings.HasPrefix(line, "<!-- FILE: ") && strings.HasSuffix(line, " -->") {
-			currentFile = strings.TrimSuffix(strings.TrimPrefix(line, "<!-- FILE: "), " -->")
-		}
-	}
+	currentFile := context.FilePath
 
 	// Walk through the AST
 	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
@@ -201,6 +247,12 @@ func (sms *StructuredMarkdownStrategy) Extract(content []byte, context Extractio
 				return ast.WalkContinue, nil
 			}
 
+			// Badges and links such as "Source Code" annotate another item
+			entry := blockSource(v, content)
+			if IsAnnotationLink(entry, urlStr) {
+				return ast.WalkSkipChildren, nil
+			}
+
 			// Get link text
 			linkText := getNodeText(v, content)
 			if linkText == "" {
@@ -235,11 +287,13 @@ func (sms *StructuredMarkdownStrategy) Extract(content []byte, context Extractio
 				RawText:        getNodeText(n.Parent(), content),
 				HeadingContext: hierarchy,
 				Metadata: map[string]interface{}{
-					"file_path": currentFile,
-					"category":  category,
-					"hierarchy": hierarchy,
+					"file_path":   currentFile,
+					"line_number": nodeLine(v, content),
+					"category":    category,
+					"hierarchy":   hierarchy,
 				},
 			}
+			EntryAnnotations(entry, urlStr).Apply(item.Metadata)
 
 			items = append(items, item)
 		}
@@ -285,18 +339,12 @@ func (rms *RegexMarkdownStrategy) Extract(content []byte, context ExtractionCont
 
 	var items []RawItem
 	var currentHeading string
-	var currentFile string
+	currentFile := context.FilePath
 
 	for lineNum, line := range lines {
 		originalLine := line
 		line = strings.TrimSpace(line)
 
-		// Track file changes
-		if strings.HasPrefix(line, "<!-- FILE: ") && strings.HasSuffix(line, " -->") {
-			currentFile = strings.TrimSuffix(strings.TrimPrefix(line, "<!-- FILE: "), " -->")
-			continue
-		}
-
 		// Track headings
 		if strings.HasPrefix(line, "#") {
 			currentHeading = common.CleanCategory(strings.TrimSpace(strings.TrimLeft(line, "#")))
@@ -306,8 +354,8 @@ func (rms *RegexMarkdownStrategy) Extract(content []byte, context ExtractionCont
 			continue
 		}
 
-		// Use existing markdown link extraction
-		linkText, urlStr := common.ExtractMarkdownLink(line)
+		// Find the link the entry is about, skipping badges and such
+		linkText, urlStr := entryMainLink(line)
 		if linkText != "" && urlStr != "" {
 			// Skip invalid links
 			if len(linkText) <= 1 || isLocalURL(urlStr) {
@@ -320,7 +368,7 @@ func (rms *RegexMarkdownStrategy) Extract(content []byte, context ExtractionCont
 			}
 
 			// Clean the description using existing utilities
-			description := common.CleanDescription(common.CleanMarkdown(originalLine))
+			description := common.CleanDescription(common.CleanMarkdown(StripAnnotations(originalLine)))
 
 			item := RawItem{
 				URL:            urlStr,
@@ -335,6 +383,7 @@ func (rms *RegexMarkdownStrategy) Extract(content []byte, context ExtractionCont
 					"category":    category,
 				},
 			}
+			EntryAnnotations(originalLine, urlStr).Apply(item.Metadata)
 
 			items = append(items, item)
 		}
@@ -375,18 +424,12 @@ func (sms *SimpleMarkdownStrategy) Extract(content []byte, context ExtractionCon
 
 	var items []RawItem
 	var currentHeading string
-	var currentFile string
+	currentFile := context.FilePath
 
 	for lineNum, line := range lines {
 		originalLine := line
 		line = strings.TrimSpace(line)
 
-		// Track file changes
-		if strings.HasPrefix(line, "<!-- FILE: ") && strings.HasSuffix(line, " -->") {
-			currentFile = strings.TrimSuffix(strings.TrimPrefix(line, "<!-- FILE: "), " -->")
-			continue
-		}
-
 		// Track headings
 		if strings.HasPrefix(line, "#") {
 			currentHeading = common.CleanCategory(strings.TrimSpace(strings.TrimLeft(line, "#")))
@@ -396,8 +439,8 @@ func (sms *SimpleMarkdownStrategy) Extract(content []byte, context ExtractionCon
 			continue
 		}
 
-		// Use existing URL extraction
-		urlStr := common.ExtractURL(line)
+		// Use existing URL extraction, skipping badges and such
+		urlStr := entryURL(line)
 		if urlStr != "" {
 			// Skip local URLs
 			if isLocalURL(urlStr) {
@@ -405,7 +448,7 @@ func (sms *SimpleMarkdownStrategy) Extract(content []byte, context ExtractionCon
 			}
 
 			// Try to extract a name from the surrounding text
-			name := extractNameFromContext(originalLine, urlStr)
+			name := extractNameFromContext(StripAnnotations(originalLine), urlStr)
 			if name == "" {
 				// Use domain as fallback name
 				domain := common.ExtractDomain(urlStr)
@@ -422,7 +465,7 @@ func (sms *SimpleMarkdownStrategy) Extract(content []byte, context ExtractionCon
 			}
 
 			// Clean the description using existing utilities
-			description := common.CleanDescription(common.CleanMarkdown(originalLine))
+			description := common.CleanDescription(common.CleanMarkdown(StripAnnotations(originalLine)))
 
 			item := RawItem{
 				URL:            urlStr,
@@ -437,6 +480,7 @@ func (sms *SimpleMarkdownStrategy) Extract(content []byte, context ExtractionCon
 					"category":    category,
 				},
 			}
+			EntryAnnotations(originalLine, urlStr).Apply(item.Metadata)
 
 			items = append(items, item)
 		}
@@ -497,6 +541,49 @@ func getNodeText(n ast.Node, source []byte) string {
 	return strings.TrimSpace(text.String())
 }
 
+// nodeLine returns the 1-based line of content that an inline node starts
+// on, or 0 when it can't be located
+func nodeLine(n ast.Node, content []byte) int {
+	offset := -1
+	for c := n.FirstChild(); c != nil && offset < 0; c = c.NextSibling() {
+		if t, ok := c.(*ast.Text); ok {
+			offset = t.Segment.Start
+		}
+	}
+
+	// Fall back to the first line of the enclosing block
+	if offset < 0 {
+		for p := n.Parent(); p != nil; p = p.Parent() {
+			if p.Type() == ast.TypeBlock && p.Lines().Len() > 0 {
+				offset = p.Lines().At(0).Start
+				break
+			}
+		}
+	}
+	if offset < 0 || offset > len(content) {
+		return 0
+	}
+
+	return bytes.Count(content[:offset], []byte("\n")) + 1
+}
+
+// blockSource returns the markdown of the block an inline node is in
+func blockSource(n ast.Node, content []byte) string {
+	for p := n.Parent(); p != nil; p = p.Parent() {
+		if p.Type() != ast.TypeBlock || p.Lines().Len() == 0 {
+			continue
+		}
+		var source bytes.Buffer
+		lines := p.Lines()
+		for i := 0; i < lines.Len(); i++ {
+			segment := lines.At(i)
+			source.Write(segment.Value(content))
+		}
+		return source.String()
+	}
+	return ""
+}
+
 // Note: cleanCategory function removed - using common.CleanCategory instead
 
 // isMisleadingHeading checks if a heading is likely misleading (like "Contents")
@@ -0,0 +1,83 @@
+package extractors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const animals = `# Animals
+
+## Marsupials
+
+- [Koala](https://koala.com/) - Sleeps most of the day
+- [Kangaroo](https://kangaroo.com/) - Hops around
+
+## Birds
+
+Penguin https://penguin.com/ cannot fly
+`
+
+func TestMarkdownExtractorMergesStrategies(t *testing.T) {
+	extractor := NewMarkdownExtractor(ProcessingConfig{})
+	result, err := extractor.ExtractFile([]byte(animals), SourceMetadata{Name: "animals"}, "animals.md")
+	require.NoError(t, err)
+
+	var urls []string
+	strategies := make(map[string]string)
+	for _, item := range result.Items {
+		urls = append(urls, item.URL)
+		strategies[item.URL], _ = item.Metadata["strategy"].(string)
+		assert.Equal(t, "animals.md", item.Metadata["file_path"])
+	}
+
+	// Ordered by line, with the plain URL only the simple strategy finds
+	assert.Equal(t, []string{"https://koala.com/", "https://kangaroo.com/", "https://penguin.com/"}, urls)
+	assert.Equal(t, "simple", strategies["https://penguin.com/"])
+	assert.NotEqual(t, "simple", strategies["https://koala.com/"])
+	assert.Greater(t, result.Stats.DuplicateItems, 0)
+}
+
+func TestMarkdownExtractorSelectsStrategies(t *testing.T) {
+	extractor := NewMarkdownExtractor(ProcessingConfig{ExtractionStrategies: []string{"Regex", "unknown"}})
+	require.Len(t, extractor.strategies, 1)
+
+	result, err := extractor.ExtractFile([]byte(animals), SourceMetadata{Name: "animals"}, "animals.md")
+	require.NoError(t, err)
+	require.Len(t, result.Items, 2)
+	for _, item := range result.Items {
+		assert.Equal(t, "regex", item.Metadata["strategy"])
+	}
+	assert.Equal(t, "regex", result.Stats.ExtractorUsed)
+}
+
+func TestCheckStrategies(t *testing.T) {
+	assert.NoError(t, CheckStrategies(nil))
+	assert.NoError(t, CheckStrategies([]string{"Table", "regex"}))
+	assert.ErrorContains(t, CheckStrategies([]string{"regex", "regx"}), "regx")
+}
+
+func TestMergeItemsKeepsHighestConfidence(t *testing.T) {
+	items := []RawItem{
+		{URL: "https://koala.com/", Name: "https://koala.com/", Metadata: map[string]interface{}{"strategy": "simple", "line_number": 3}},
+		{URL: "https://koala.com/", Name: "Koala", Description: "Sleeps most of the day", Metadata: map[string]interface{}{"strategy": "regex", "line_number": 3}},
+		{URL: "https://emu.com/", Name: "Emu", Metadata: map[string]interface{}{"line_number": 1}},
+	}
+
+	merged := MergeItems(items)
+	require.Len(t, merged, 2)
+	assert.Equal(t, "https://emu.com/", merged[0].URL)
+	assert.Equal(t, "regex", merged[1].Metadata["strategy"])
+}
+
+func TestMergeItemsComparesCanonicalURLs(t *testing.T) {
+	items := []RawItem{
+		{URL: "https://koala.com/", Name: "Koala", Metadata: map[string]interface{}{"line_number": 1}},
+		{URL: "http://www.koala.com?utm_source=awesome", Name: "Koala", Metadata: map[string]interface{}{"line_number": 2}},
+	}
+
+	merged := MergeItems(items)
+	require.Len(t, merged, 1)
+	assert.Equal(t, "https://koala.com/", merged[0].URL)
+}
@@ -0,0 +1,85 @@
+package extractors
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"freectl/internal/common"
+)
+
+// Confidence scores how complete an extracted item is, from 0 to 1
+func Confidence(item RawItem) float64 {
+	confidence := 0.5 // Base confidence
+
+	// Higher confidence if we have a good name
+	if item.Name != "" && len(item.Name) > 3 && item.Name != item.URL {
+		confidence += 0.2
+	}
+
+	// Higher confidence if we have description
+	if item.Description != "" && len(item.Description) > 10 {
+		confidence += 0.15
+	}
+
+	// Higher confidence if we have heading context
+	if len(item.HeadingContext) > 0 {
+		confidence += 0.1
+	}
+
+	// Higher confidence if table headers labelled the fields
+	if columns, _ := item.Metadata["columns"].(int); columns > 1 {
+		confidence += 0.15
+	}
+
+	// Higher confidence if URL looks valid
+	if parsedURL, err := url.Parse(item.URL); err == nil {
+		if parsedURL.Host != "" && !strings.Contains(parsedURL.Host, "localhost") {
+			confidence += 0.05
+		}
+	}
+
+	// Cap at 1.0
+	if confidence > 1.0 {
+		confidence = 1.0
+	}
+
+	return confidence
+}
+
+// MergeItems folds items linking to the same page into the one with the highest
+// confidence. On a tie the earliest item wins, so strategies listed first
+// are preferred. The result is ordered by where items appear in the file.
+func MergeItems(items []RawItem) []RawItem {
+	index := make(map[string]int)
+	var merged []RawItem
+	var scores []float64
+
+	for _, item := range items {
+		confidence := Confidence(item)
+		key := common.CanonicalURL(item.URL)
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(merged)
+			merged = append(merged, item)
+			scores = append(scores, confidence)
+			continue
+		}
+		if confidence > scores[i] {
+			merged[i] = item
+			scores[i] = confidence
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return itemLine(merged[i]) < itemLine(merged[j])
+	})
+
+	return merged
+}
+
+// itemLine returns the line an item was found on, or 0 when unknown
+func itemLine(item RawItem) int {
+	line, _ := item.Metadata["line_number"].(int)
+	return line
+}
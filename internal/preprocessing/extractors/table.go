@@ -0,0 +1,377 @@
+package extractors
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"freectl/internal/common"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// Table column roles recognised from header names
+const (
+	ColumnName        = "name"
+	ColumnURL         = "url"
+	ColumnDescription = "description"
+	ColumnLicense     = "license"
+	ColumnStars       = "stars"
+	ColumnLanguage    = "language"
+	ColumnPlatform    = "platform"
+)
+
+// tableDelimiter matches the row separating a GFM table header from its body
+var tableDelimiter = regexp.MustCompile(`(?m)^\s*\|?\s*:?-{3,}:?\s*\|`)
+
+// columnHeaders maps lowercase header names to the role of their column
+var columnHeaders = map[string]string{
+	"name":             ColumnName,
+	"project":          ColumnName,
+	"title":            ColumnName,
+	"tool":             ColumnName,
+	"software":         ColumnName,
+	"app":              ColumnName,
+	"application":      ColumnName,
+	"resource":         ColumnName,
+	"service":          ColumnName,
+	"url":              ColumnURL,
+	"link":             ColumnURL,
+	"links":            ColumnURL,
+	"website":          ColumnURL,
+	"homepage":         ColumnURL,
+	"site":             ColumnURL,
+	"description":      ColumnDescription,
+	"desc":             ColumnDescription,
+	"about":            ColumnDescription,
+	"summary":          ColumnDescription,
+	"details":          ColumnDescription,
+	"notes":            ColumnDescription,
+	"license":          ColumnLicense,
+	"licence":          ColumnLicense,
+	"stars":            ColumnStars,
+	"github stars":     ColumnStars,
+	"★":                ColumnStars,
+	"⭐":                ColumnStars,
+	"language":         ColumnLanguage,
+	"lang":             ColumnLanguage,
+	"written in":       ColumnLanguage,
+	"platform":         ColumnPlatform,
+	"platforms":        ColumnPlatform,
+	"os":               ColumnPlatform,
+	"operating system": ColumnPlatform,
+}
+
+// TableColumnRole returns the role of a table column from its header, such as
+// ColumnName or ColumnLicense, or "" when the header isn't recognised
+func TableColumnRole(header string) string {
+	header = strings.ToLower(strings.Trim(strings.TrimSpace(header), "*_`:"))
+	return columnHeaders[header]
+}
+
+// TableMarkdownStrategy extracts one item per row of GFM tables, mapping
+// columns to fields by their headers
+type TableMarkdownStrategy struct {
+	markdown goldmark.Markdown
+}
+
+// NewTableMarkdownStrategy creates a new table strategy. md must parse GFM
+// tables.
+func NewTableMarkdownStrategy(md goldmark.Markdown) Strategy {
+	return &TableMarkdownStrategy{
+		markdown: md,
+	}
+}
+
+// Extract extracts items from the rows of every table in content
+func (tms *TableMarkdownStrategy) Extract(content []byte, context ExtractionContext) ([]RawItem, error) {
+	doc := tms.markdown.Parser().Parse(text.NewReader(content))
+
+	var items []RawItem
+	headings := make(map[int]string)
+
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch v := n.(type) {
+		case *ast.Heading:
+			cleanHeading := common.CleanCategory(getNodeText(v, content))
+			if isMisleadingHeading(cleanHeading) || common.IsInvalidCategory(cleanHeading) {
+				return ast.WalkSkipChildren, nil
+			}
+
+			// A heading replaces any deeper ones
+			headings[v.Level] = cleanHeading
+			for level := v.Level + 1; level <= 6; level++ {
+				delete(headings, level)
+			}
+
+		case *east.Table:
+			var hierarchy []string
+			for level := 1; level <= 6; level++ {
+				if heading, ok := headings[level]; ok {
+					hierarchy = append(hierarchy, heading)
+				}
+			}
+			items = append(items, tms.extractTable(v, content, context, hierarchy)...)
+			return ast.WalkSkipChildren, nil
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return items, nil
+}
+
+// extractTable returns an item for every row of table that has a link
+func (tms *TableMarkdownStrategy) extractTable(table *east.Table, content []byte, context ExtractionContext, hierarchy []string) []RawItem {
+	var roles []string
+	var items []RawItem
+
+	category := "Uncategorized"
+	if len(hierarchy) > 0 {
+		category = hierarchy[len(hierarchy)-1]
+	}
+
+	for row := table.FirstChild(); row != nil; row = row.NextSibling() {
+		var cells []ast.Node
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			cells = append(cells, cell)
+		}
+
+		if _, ok := row.(*east.TableHeader); ok {
+			for _, cell := range cells {
+				roles = append(roles, TableColumnRole(getNodeText(cell, content)))
+			}
+			continue
+		}
+
+		item, ok := tableRowItem(cells, roles, content)
+		if !ok || isLocalURL(item.URL) {
+			continue
+		}
+
+		item.HeadingContext = hierarchy
+		item.Metadata["columns"] = recognisedColumns(roles)
+		item.Metadata["file_path"] = context.FilePath
+		line := rowLine(row, content)
+		item.Metadata["line_number"] = line
+		item.Metadata["category"] = category
+		item.Metadata["hierarchy"] = hierarchy
+		EntryAnnotations(sourceLine(content, line), item.URL).Apply(item.Metadata)
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// tableRowItem builds an item from the cells of a table row, using roles to
+// know what each column holds
+func tableRowItem(cells []ast.Node, roles []string, content []byte) (RawItem, bool) {
+	item := RawItem{Metadata: map[string]interface{}{}}
+	var texts, others []string
+	var linkText string
+
+	cellsWithRole := func(role string) []int {
+		var indexes []int
+		for i, r := range roles {
+			if r == role && i < len(cells) {
+				indexes = append(indexes, i)
+			}
+		}
+		return indexes
+	}
+
+	for _, cell := range cells {
+		texts = append(texts, strings.TrimSpace(getNodeText(cell, content)))
+	}
+
+	// Prefer the link of a URL column, then of the name column, then any
+	order := append(cellsWithRole(ColumnURL), cellsWithRole(ColumnName)...)
+	for i := range cells {
+		order = append(order, i)
+	}
+	for _, i := range order {
+		if url, text := cellLink(cells[i], content); url != "" {
+			item.URL, linkText = url, text
+			break
+		}
+	}
+	if item.URL == "" {
+		return item, false
+	}
+
+	for i, text := range texts {
+		role := ""
+		if i < len(roles) {
+			role = roles[i]
+		}
+		if text == "" {
+			continue
+		}
+
+		switch role {
+		case ColumnName:
+			item.Name = text
+		case ColumnDescription:
+			item.Description = text
+		case ColumnLicense:
+			item.Metadata["license"] = text
+		case ColumnStars:
+			if stars := parseStars(text); stars > 0 {
+				item.Metadata["stars"] = stars
+			}
+		case ColumnLanguage:
+			item.Metadata["language"] = text
+		case ColumnPlatform:
+			item.Metadata["platforms"] = splitList(text)
+		case ColumnURL:
+		default:
+			others = append(others, text)
+		}
+	}
+
+	if item.Name == "" {
+		item.Name = linkText
+	}
+	if item.Name == "" {
+		item.Name = common.ExtractDomain(item.URL)
+	}
+
+	// Without a description column, the unrecognised columns describe it
+	if item.Description == "" {
+		for _, text := range others {
+			if text != item.Name {
+				item.Description = joinDescription(item.Description, text)
+			}
+		}
+	}
+
+	item.Context = strings.Join(texts, " | ")
+	item.RawText = item.Context
+	return item, true
+}
+
+// recognisedColumns counts the columns whose role is known
+func recognisedColumns(roles []string) int {
+	count := 0
+	for _, role := range roles {
+		if role != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// cellLink returns the destination and text of the first link in a cell, or
+// the cell text when it is a bare URL
+func cellLink(cell ast.Node, content []byte) (string, string) {
+	var url, linkText string
+	ast.Walk(cell, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering || url != "" {
+			return ast.WalkContinue, nil
+		}
+		switch v := n.(type) {
+		case *ast.Link:
+			// Badges only annotate the row
+			if _, badge := v.FirstChild().(*ast.Image); badge || IsBadgeURL(string(v.Destination)) {
+				return ast.WalkSkipChildren, nil
+			}
+			url, linkText = string(v.Destination), strings.TrimSpace(getNodeText(v, content))
+			return ast.WalkStop, nil
+		case *ast.AutoLink:
+			url = string(v.URL(content))
+			return ast.WalkStop, nil
+		}
+		return ast.WalkContinue, nil
+	})
+
+	if url == "" {
+		url = common.ExtractURL(getNodeText(cell, content))
+	}
+	return url, linkText
+}
+
+// rowLine returns the 1-based line a table row is on, or 0 when unknown
+func rowLine(row ast.Node, content []byte) int {
+	line := 0
+	ast.Walk(row, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if t, ok := n.(*ast.Text); ok && entering {
+			line = bytes.Count(content[:t.Segment.Start], []byte("\n")) + 1
+			return ast.WalkStop, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return line
+}
+
+// sourceLine returns the 1-based line of content, or "" when out of range
+func sourceLine(content []byte, line int) string {
+	lines := bytes.Split(content, []byte("\n"))
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	return string(lines[line-1])
+}
+
+// parseStars parses star counts such as "1,234", "12.3k" or "⭐ 540"
+func parseStars(s string) int {
+	s = strings.ToLower(strings.TrimSpace(strings.Trim(s, "⭐★ ")))
+	s = strings.ReplaceAll(s, ",", "")
+
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(s, "k"):
+		multiplier, s = 1000, strings.TrimSuffix(s, "k")
+	case strings.HasSuffix(s, "m"):
+		multiplier, s = 1000000, strings.TrimSuffix(s, "m")
+	}
+
+	n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return int(n * multiplier)
+}
+
+// splitList splits a cell listing several values, such as "Windows, macOS"
+func splitList(s string) []string {
+	var values []string
+	for _, value := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == '/' || r == ';' || r == '|'
+	}) {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// joinDescription appends text to a description
+func joinDescription(description, text string) string {
+	if description == "" {
+		return text
+	}
+	return description + " - " + text
+}
+
+// CanHandle returns true if content has a GFM table delimiter row
+func (tms *TableMarkdownStrategy) CanHandle(content []byte) bool {
+	return tableDelimiter.Match(content)
+}
+
+// Priority returns the priority of this strategy
+func (tms *TableMarkdownStrategy) Priority() int {
+	return 150
+}
+
+// Name returns the name of this strategy
+func (tms *TableMarkdownStrategy) Name() string {
+	return "table"
+}
@@ -0,0 +1,76 @@
+package extractors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const editors = `# Software
+
+## Editors
+
+| Name | Description | License | Stars | Language | Platform |
+| --- | --- | --- | --- | --- | --- |
+| [Neovim](https://neovim.io/) | Hyperextensible Vim-based editor | Apache-2.0 | 82.1k | C | Linux, macOS / Windows |
+| [Helix](https://helix-editor.com/) | A post-modern modal editor | MPL-2.0 | ⭐ 1,234 | Rust | Linux |
+| Emacs | No link here | GPL-3.0 | | Lisp | |
+
+## Hosting
+
+| Service | Link | Notes |
+|---|---|---|
+| Codeberg | https://codeberg.org/ | Non-profit |
+`
+
+func TestTableStrategyMapsColumns(t *testing.T) {
+	extractor := NewMarkdownExtractor(ProcessingConfig{})
+	result, err := extractor.ExtractFile([]byte(editors), SourceMetadata{Name: "editors"}, "editors.md")
+	require.NoError(t, err)
+
+	items := make(map[string]RawItem)
+	for _, item := range result.Items {
+		items[item.URL] = item
+	}
+	require.Len(t, items, 3)
+
+	neovim := items["https://neovim.io/"]
+	assert.Equal(t, "Neovim", neovim.Name)
+	assert.Equal(t, "Hyperextensible Vim-based editor", neovim.Description)
+	assert.Equal(t, "table", neovim.Metadata["strategy"])
+	assert.Equal(t, 7, neovim.Metadata["line_number"])
+	assert.Equal(t, "Editors", neovim.Metadata["category"])
+	assert.Equal(t, "Apache-2.0", neovim.Metadata["license"])
+	assert.Equal(t, 82100, neovim.Metadata["stars"])
+	assert.Equal(t, "C", neovim.Metadata["language"])
+	assert.Equal(t, []string{"Linux", "macOS", "Windows"}, neovim.Metadata["platforms"])
+
+	assert.Equal(t, 1234, items["https://helix-editor.com/"].Metadata["stars"])
+
+	codeberg := items["https://codeberg.org/"]
+	assert.Equal(t, "Codeberg", codeberg.Name)
+	assert.Equal(t, "Non-profit", codeberg.Description)
+	assert.Equal(t, "Hosting", codeberg.Metadata["category"])
+}
+
+func TestTableColumnRole(t *testing.T) {
+	assert.Equal(t, ColumnName, TableColumnRole(" **Project** "))
+	assert.Equal(t, ColumnURL, TableColumnRole("Website"))
+	assert.Equal(t, ColumnStars, TableColumnRole("⭐"))
+	assert.Equal(t, ColumnPlatform, TableColumnRole("OS"))
+	assert.Empty(t, TableColumnRole("Comments?"))
+}
+
+func TestParseStars(t *testing.T) {
+	for input, want := range map[string]int{
+		"540":   540,
+		"1,234": 1234,
+		"12.3k": 12300,
+		"⭐ 2K":  2000,
+		"1.5M":  1500000,
+		"n/a":   0,
+	} {
+		assert.Equal(t, want, parseStars(input), input)
+	}
+}
@@ -0,0 +1,257 @@
+package preprocessing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"freectl/internal/common"
+)
+
+// maxRedirects is how many redirects a link check follows
+const maxRedirects = 10
+
+// LinkHealth is the outcome of checking whether an item's link still works
+type LinkHealth struct {
+	StatusCode  int       `json:"status_code,omitempty"`
+	Error       string    `json:"error,omitempty"`        // why the link couldn't be reached
+	RedirectURL string    `json:"redirect_url,omitempty"` // where the link first redirected to
+	FinalURL    string    `json:"final_url,omitempty"`    // where it ended up after every redirect
+	CheckedAt   time.Time `json:"checked_at,omitzero"`
+}
+
+// Checked reports whether the link has been checked
+func (h LinkHealth) Checked() bool {
+	return !h.CheckedAt.IsZero()
+}
+
+// Dead reports whether the link was unreachable or its page is gone. Sites
+// refusing automated requests with 401, 403 or 429 aren't counted as dead.
+func (h LinkHealth) Dead() bool {
+	if !h.Checked() {
+		return false
+	}
+	if h.Error != "" {
+		return true
+	}
+	return h.StatusCode == http.StatusNotFound || h.StatusCode == http.StatusGone || h.StatusCode >= 500
+}
+
+// Redirected reports whether the link led to another URL
+func (h LinkHealth) Redirected() bool {
+	return h.FinalURL != ""
+}
+
+// LinkCheckConfig controls how links are checked
+type LinkCheckConfig struct {
+	Concurrency int           // hosts checked at once
+	HostDelay   time.Duration // pause between requests to the same host
+	Timeout     time.Duration // for each request, including redirects
+	MaxAge      time.Duration // links checked more recently than this aren't checked again
+	UserAgent   string
+}
+
+// DefaultLinkCheckConfig returns the default link checking configuration
+func DefaultLinkCheckConfig() LinkCheckConfig {
+	return LinkCheckConfig{
+		Concurrency: 8,
+		HostDelay:   time.Second,
+		Timeout:     15 * time.Second,
+		MaxAge:      7 * 24 * time.Hour,
+		UserAgent:   "freectl-link-checker/1.0",
+	}
+}
+
+// LinkChecker checks links with bounded concurrency. Requests to the same
+// host are made one at a time, HostDelay apart, so large lists don't hammer
+// the sites they link to.
+type LinkChecker struct {
+	config    LinkCheckConfig
+	transport http.RoundTripper
+}
+
+// NewLinkChecker creates a link checker
+func NewLinkChecker(config LinkCheckConfig) *LinkChecker {
+	defaults := DefaultLinkCheckConfig()
+	if config.Concurrency <= 0 {
+		config.Concurrency = defaults.Concurrency
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+	if config.UserAgent == "" {
+		config.UserAgent = defaults.UserAgent
+	}
+	return &LinkChecker{config: config, transport: http.DefaultTransport}
+}
+
+// Stale reports whether a link is due to be checked
+func (lc *LinkChecker) Stale(health LinkHealth) bool {
+	return !health.Checked() || time.Since(health.CheckedAt) >= lc.config.MaxAge
+}
+
+// Check requests a link and reports its health. HEAD is tried first, and
+// GET when the server doesn't answer HEAD properly.
+func (lc *LinkChecker) Check(ctx context.Context, link string) LinkHealth {
+	health := lc.request(ctx, http.MethodHead, link)
+	if health.Error != "" || health.StatusCode == http.StatusMethodNotAllowed || health.StatusCode == http.StatusNotImplemented ||
+		health.StatusCode == http.StatusForbidden || health.StatusCode == http.StatusBadRequest || health.StatusCode == http.StatusNotFound {
+		if ctx.Err() == nil {
+			health = lc.request(ctx, http.MethodGet, link)
+		}
+	}
+	return health
+}
+
+// request makes a single request to link, following redirects
+func (lc *LinkChecker) request(ctx context.Context, method, link string) LinkHealth {
+	health := LinkHealth{CheckedAt: time.Now()}
+
+	client := &http.Client{
+		Transport: lc.transport,
+		Timeout:   lc.config.Timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if health.RedirectURL == "" {
+				health.RedirectURL = req.URL.String()
+			}
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			return nil
+		},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, link, nil)
+	if err != nil {
+		health.Error = err.Error()
+		return health
+	}
+	req.Header.Set("User-Agent", lc.config.UserAgent)
+	req.Header.Set("Accept", "text/html,*/*;q=0.8")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		health.Error = linkError(err)
+		return health
+	}
+	resp.Body.Close()
+
+	health.StatusCode = resp.StatusCode
+	if final := resp.Request.URL.String(); health.RedirectURL != "" && final != link {
+		health.FinalURL = final
+	}
+	return health
+}
+
+// linkError describes why a link couldn't be reached without repeating it
+func linkError(err error) string {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timed out"
+	}
+	return err.Error()
+}
+
+// CheckAll checks every distinct link in links, returning the results keyed
+// by the links as given. progress, when set, is called after each check.
+// Links not checked before ctx is done are left out of the results.
+func (lc *LinkChecker) CheckAll(ctx context.Context, links []string, progress func(link string, health LinkHealth)) map[string]LinkHealth {
+	// Variants of a URL are checked once, and links are queued by host so
+	// each host is visited by one worker at a time
+	byCanonical := make(map[string][]string)
+	hosts := make(map[string][]string)
+	var hostOrder []string
+	for _, link := range links {
+		canonical := common.CanonicalURL(link)
+		if _, ok := byCanonical[canonical]; !ok {
+			host := linkHost(link)
+			if _, ok := hosts[host]; !ok {
+				hostOrder = append(hostOrder, host)
+			}
+			hosts[host] = append(hosts[host], link)
+		}
+		byCanonical[canonical] = append(byCanonical[canonical], link)
+	}
+
+	results := make(map[string]LinkHealth, len(links))
+	var mu sync.Mutex
+
+	queue := make(chan string)
+	var wg sync.WaitGroup
+	for range min(lc.config.Concurrency, len(hostOrder)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for host := range queue {
+				for i, link := range hosts[host] {
+					if i > 0 && !sleepContext(ctx, lc.config.HostDelay) {
+						return
+					}
+					if ctx.Err() != nil {
+						return
+					}
+
+					health := lc.Check(ctx, link)
+					if ctx.Err() != nil {
+						return // cancelled rather than checked
+					}
+
+					mu.Lock()
+					for _, variant := range byCanonical[common.CanonicalURL(link)] {
+						results[variant] = health
+					}
+					if progress != nil {
+						progress(link, health)
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, host := range hostOrder {
+		select {
+		case queue <- host:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	return results
+}
+
+// linkHost returns the host requests for a link are made to
+func linkHost(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Host)
+}
+
+// sleepContext waits for d, returning false if ctx is done first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
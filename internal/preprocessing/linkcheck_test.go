@@ -0,0 +1,168 @@
+package preprocessing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"freectl/internal/sources"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// linkServer serves the pages link checks are tested against, counting the
+// requests made for each path
+func linkServer(t *testing.T) (*httptest.Server, map[string]int) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/gone", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusGone) })
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) { http.Redirect(w, r, "/ok", http.StatusMovedPermanently) })
+	mux.HandleFunc("/get-only", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func TestLinkCheckerCheck(t *testing.T) {
+	server, _ := linkServer(t)
+	checker := NewLinkChecker(LinkCheckConfig{Timeout: time.Second})
+	ctx := context.Background()
+
+	ok := checker.Check(ctx, server.URL+"/ok")
+	assert.Equal(t, http.StatusOK, ok.StatusCode)
+	assert.False(t, ok.Dead())
+	assert.False(t, ok.Redirected())
+	assert.True(t, ok.Checked())
+
+	gone := checker.Check(ctx, server.URL+"/gone")
+	assert.Equal(t, http.StatusGone, gone.StatusCode)
+	assert.True(t, gone.Dead())
+
+	moved := checker.Check(ctx, server.URL+"/moved")
+	assert.Equal(t, http.StatusOK, moved.StatusCode)
+	assert.Equal(t, server.URL+"/ok", moved.RedirectURL)
+	assert.Equal(t, server.URL+"/ok", moved.FinalURL)
+
+	// Servers that refuse HEAD are asked with GET
+	assert.Equal(t, http.StatusOK, checker.Check(ctx, server.URL+"/get-only").StatusCode)
+
+	unreachable := checker.Check(ctx, "http://127.0.0.1:1/")
+	assert.NotEmpty(t, unreachable.Error)
+	assert.True(t, unreachable.Dead())
+}
+
+func TestLinkCheckerCheckAll(t *testing.T) {
+	server, requests := linkServer(t)
+	checker := NewLinkChecker(LinkCheckConfig{Concurrency: 4, HostDelay: 10 * time.Millisecond, Timeout: time.Second})
+
+	links := []string{server.URL + "/ok", server.URL + "/ok/", server.URL + "/gone", server.URL + "/moved"}
+	var progressed []string
+	results := checker.CheckAll(context.Background(), links, func(link string, health LinkHealth) {
+		progressed = append(progressed, link)
+	})
+
+	// Variants of a URL are checked once but reported for each
+	require.Len(t, results, 4)
+	assert.Equal(t, http.StatusOK, results[server.URL+"/ok/"].StatusCode)
+	assert.True(t, results[server.URL+"/gone"].Dead())
+	assert.Len(t, progressed, 3)
+	assert.Equal(t, 2, requests["/ok"]) // once for itself and once following /moved
+
+	// Nothing is checked once cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Empty(t, checker.CheckAll(ctx, links, nil))
+}
+
+func TestLinkCheckerStale(t *testing.T) {
+	checker := NewLinkChecker(LinkCheckConfig{MaxAge: time.Hour})
+	assert.True(t, checker.Stale(LinkHealth{}))
+	assert.False(t, checker.Stale(LinkHealth{StatusCode: 200, CheckedAt: time.Now()}))
+	assert.True(t, checker.Stale(LinkHealth{StatusCode: 200, CheckedAt: time.Now().Add(-2 * time.Hour)}))
+}
+
+func TestProcessSourceChecksLinks(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server, requests := linkServer(t)
+
+	cacheDir := t.TempDir()
+	sourceDir := filepath.Join(cacheDir, "demo")
+	require.NoError(t, os.MkdirAll(sourceDir, 0755))
+	list := "# Tools\n\n- [Alive](" + server.URL + "/ok) - Still there\n- [Gone](" + server.URL + "/gone) - Long gone\n"
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "list.md"), []byte(list), 0644))
+
+	config := DefaultProcessingConfig()
+	config.ValidateURLs = true
+	engine := NewProcessingEngine(cacheDir, config)
+	engine.SetLinkChecker(NewLinkChecker(LinkCheckConfig{Timeout: time.Second, MaxAge: time.Hour}))
+
+	source := sources.Source{Name: "demo", Path: sourceDir, Type: sources.SourceTypeGit, Enabled: true}
+	require.NoError(t, engine.ProcessSource(source))
+
+	processed, err := engine.storage.Load("demo")
+	require.NoError(t, err)
+	require.Len(t, processed.Items, 2)
+	assert.False(t, processed.Items[0].Health.Dead())
+	assert.True(t, processed.Items[1].Health.Dead())
+
+	// Reprocessing keeps recent checks rather than checking again
+	require.NoError(t, engine.ReprocessSource(source))
+	assert.Equal(t, 1, requests["/ok"])
+	processed, err = engine.storage.Load("demo")
+	require.NoError(t, err)
+	assert.True(t, processed.Items[1].Health.Dead())
+
+	// Checking every link does request them again
+	_, err = engine.CheckLinks(context.Background(), "demo", true, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests["/ok"])
+}
+
+func TestProcessSourceChecksLinksOfUnchangedSource(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server, requests := linkServer(t)
+
+	cacheDir := t.TempDir()
+	sourceDir := filepath.Join(cacheDir, "demo")
+	require.NoError(t, os.MkdirAll(sourceDir, 0755))
+	list := "# Tools\n\n- [Alive](" + server.URL + "/ok) - Still there\n- [Gone](" + server.URL + "/gone) - Long gone\n"
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "list.md"), []byte(list), 0644))
+
+	source := sources.Source{Name: "demo", Path: sourceDir, Type: sources.SourceTypeGit, Enabled: true}
+	require.NoError(t, NewProcessingEngine(cacheDir, DefaultProcessingConfig()).ProcessSource(source))
+
+	// The files haven't changed, but checking links was turned on since
+	config := DefaultProcessingConfig()
+	config.ValidateURLs = true
+	engine := NewProcessingEngine(cacheDir, config)
+	engine.SetLinkChecker(NewLinkChecker(LinkCheckConfig{Timeout: time.Second, MaxAge: time.Hour}))
+	require.NoError(t, engine.ProcessSource(source))
+	assert.Equal(t, 1, requests["/ok"])
+
+	processed, err := engine.storage.Load("demo")
+	require.NoError(t, err)
+	require.Len(t, processed.Items, 2)
+	for _, item := range processed.Items {
+		assert.True(t, item.Health.Checked(), item.URL)
+	}
+	assert.True(t, processed.Items[1].Health.Dead())
+}
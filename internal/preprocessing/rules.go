@@ -0,0 +1,277 @@
+package preprocessing
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Rule assigns a category and tags to the items matching all of its
+// conditions. A condition listing several values matches any of them, and a
+// rule without conditions matches every item.
+type Rule struct {
+	Name     string   `json:"name,omitempty"`
+	Domains  []string `json:"domains,omitempty"`  // the URL's host or a subdomain of it
+	URL      string   `json:"url,omitempty"`      // regular expression the URL must match
+	Headings []string `json:"headings,omitempty"` // a heading above the item, ignoring case
+	Keywords []string `json:"keywords,omitempty"` // a word or phrase in the name or description
+	Category string   `json:"category,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Fallback bool     `json:"fallback,omitempty"` // only categorise items the list doesn't
+	Stop     bool     `json:"stop,omitempty"`     // skip the rules after this one when it matches
+
+	urlRegex *regexp.Regexp
+	keywords []*regexp.Regexp
+}
+
+// RuleSet is an ordered list of categorisation and tagging rules. The first
+// matching rule with a category decides it, and every matching rule adds its
+// tags.
+type RuleSet struct {
+	Rules []Rule `json:"rules"`
+}
+
+// RuleInput is what rules are matched against
+type RuleInput struct {
+	URL         string
+	Name        string
+	Description string
+	Headings    []string
+}
+
+// RuleMatch is the outcome of applying a rule set to an item
+type RuleMatch struct {
+	Category string   `json:"category,omitempty"`
+	Fallback bool     `json:"fallback,omitempty"` // the category only applies to uncategorised items
+	Tags     []string `json:"tags,omitempty"`
+	Matched  []int    `json:"matched"` // indexes of the matching rules
+}
+
+// GetRulesPath returns the path to the rules file
+func GetRulesPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	configDir := filepath.Join(homeDir, ".config", "freectl")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	return filepath.Join(configDir, "rules.json"), nil
+}
+
+// LoadRules loads the rules file, or returns the default rules when there
+// isn't one
+func LoadRules() (*RuleSet, error) {
+	path, err := GetRulesPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return DefaultRules(), nil
+		}
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+
+	var rules RuleSet
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse rules file %s: %w", path, err)
+	}
+	if err := rules.Compile(); err != nil {
+		return nil, fmt.Errorf("invalid rules file %s: %w", path, err)
+	}
+
+	return &rules, nil
+}
+
+// SaveRules writes rules to the rules file
+func SaveRules(rules *RuleSet) error {
+	path, err := GetRulesPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(rules, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal rules: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write rules file: %w", err)
+	}
+	return nil
+}
+
+// DefaultRules returns the rules used without a rules file. They only
+// categorise items the list doesn't, and tag items by common keywords.
+func DefaultRules() *RuleSet {
+	categories := []struct {
+		name     string
+		keywords []string
+	}{
+		{"Development", []string{"api", "library", "framework", "tool", "github", "code", "programming", "developer"}},
+		{"Media", []string{"video", "movie", "music", "streaming", "download", "torrent"}},
+		{"Education", []string{"tutorial", "course", "learn", "education", "training", "guide"}},
+		{"News", []string{"news", "blog", "article", "journalism", "magazine"}},
+		{"Security", []string{"security", "privacy", "vpn", "encryption", "hack", "cybersecurity"}},
+		{"Gaming", []string{"game", "gaming", "steam", "playstation", "xbox", "nintendo"}},
+		{"Social", []string{"social", "forum", "community", "discord", "reddit", "twitter"}},
+	}
+
+	rules := &RuleSet{}
+	for _, category := range categories {
+		rules.Rules = append(rules.Rules, Rule{
+			Name:     strings.ToLower(category.name),
+			Keywords: category.keywords,
+			Category: category.name,
+			Fallback: true,
+		})
+	}
+
+	for _, tag := range []string{
+		"free", "open-source", "tool", "library", "framework", "api", "service",
+		"tutorial", "guide", "documentation", "blog", "article", "video",
+		"github", "python", "javascript", "go", "rust", "java",
+	} {
+		rules.Rules = append(rules.Rules, Rule{
+			Name:     "tag " + tag,
+			Keywords: []string{tag},
+			Tags:     []string{tag},
+		})
+	}
+
+	// The defaults are known to be valid
+	_ = rules.Compile()
+	return rules
+}
+
+// Compile checks the rules and prepares their patterns. It must be called
+// before Match on rules that weren't loaded with LoadRules.
+func (rs *RuleSet) Compile() error {
+	for i := range rs.Rules {
+		rule := &rs.Rules[i]
+
+		rule.urlRegex = nil
+		if rule.URL != "" {
+			re, err := regexp.Compile(rule.URL)
+			if err != nil {
+				return fmt.Errorf("rule %d (%s): invalid url pattern: %w", i+1, rule.Name, err)
+			}
+			rule.urlRegex = re
+		}
+
+		rule.keywords = nil
+		for _, keyword := range rule.Keywords {
+			keyword = strings.TrimSpace(keyword)
+			if keyword == "" {
+				continue
+			}
+			// Whole words only, so "go" doesn't match "google"
+			pattern := `(?i)(^|[^\pL\pN])` + regexp.QuoteMeta(keyword) + `($|[^\pL\pN])`
+			rule.keywords = append(rule.keywords, regexp.MustCompile(pattern))
+		}
+
+		if rule.Category == "" && len(rule.Tags) == 0 && !rule.Stop {
+			return fmt.Errorf("rule %d (%s): sets no category or tags", i+1, rule.Name)
+		}
+	}
+	return nil
+}
+
+// Hash identifies the rules, so items can be processed again when they change
+func (rs *RuleSet) Hash() string {
+	data, _ := json.Marshal(rs)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Match applies the rules to an item in order
+func (rs *RuleSet) Match(input RuleInput) RuleMatch {
+	match := RuleMatch{Matched: []int{}}
+	for i, rule := range rs.Rules {
+		if !rule.matches(input) {
+			continue
+		}
+
+		match.Matched = append(match.Matched, i)
+		if match.Category == "" && rule.Category != "" {
+			match.Category, match.Fallback = rule.Category, rule.Fallback
+		}
+		match.Tags = append(match.Tags, rule.Tags...)
+
+		if rule.Stop {
+			break
+		}
+	}
+	return match
+}
+
+// matches reports whether an item meets every condition of the rule
+func (r *Rule) matches(input RuleInput) bool {
+	if len(r.Domains) > 0 && !matchesDomain(input.URL, r.Domains) {
+		return false
+	}
+
+	if r.urlRegex != nil && !r.urlRegex.MatchString(input.URL) {
+		return false
+	}
+
+	if len(r.Headings) > 0 && !matchesHeading(input.Headings, r.Headings) {
+		return false
+	}
+
+	if len(r.Keywords) > 0 {
+		text := input.Name + " " + input.Description
+		found := false
+		for _, keyword := range r.keywords {
+			if keyword.MatchString(text) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesDomain reports whether rawURL is on one of domains or a subdomain
+func matchesDomain(rawURL string, domains []string) bool {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := strings.TrimPrefix(strings.ToLower(parsedURL.Hostname()), "www.")
+
+	for _, domain := range domains {
+		domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "www.")
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesHeading reports whether any of headings is one of wanted
+func matchesHeading(headings, wanted []string) bool {
+	for _, heading := range headings {
+		for _, w := range wanted {
+			if strings.EqualFold(strings.TrimSpace(heading), strings.TrimSpace(w)) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package preprocessing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"freectl/internal/sources"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRules(t *testing.T) *RuleSet {
+	rules := &RuleSet{Rules: []Rule{
+		{Name: "wikis", Headings: []string{"Wikis"}, Category: "Knowledge Bases", Tags: []string{"wiki"}},
+		{Name: "github", Domains: []string{"github.com"}, URL: `^https://github\.com/[^/]+/[^/]+$`, Tags: []string{"repository"}},
+		{Name: "editors", Keywords: []string{"text editor"}, Category: "Editors", Fallback: true, Stop: true},
+		{Name: "after stop", Tags: []string{"everything"}},
+	}}
+	require.NoError(t, rules.Compile())
+	return rules
+}
+
+func TestRuleSetMatch(t *testing.T) {
+	rules := testRules(t)
+
+	match := rules.Match(RuleInput{URL: "https://docs.github.com/x/y", Headings: []string{"Software", "wikis"}})
+	assert.Equal(t, "Knowledge Bases", match.Category)
+	assert.False(t, match.Fallback)
+	assert.Equal(t, []string{"wiki", "everything"}, match.Tags)
+	assert.Equal(t, []int{0, 3}, match.Matched)
+
+	// Every condition has to match
+	match = rules.Match(RuleInput{URL: "https://github.com/neovim/neovim", Name: "Neovim", Description: "A text editor"})
+	assert.Equal(t, "Editors", match.Category)
+	assert.True(t, match.Fallback)
+	assert.Equal(t, []string{"repository"}, match.Tags)
+	assert.Equal(t, []int{1, 2}, match.Matched)
+
+	// Keywords match whole words
+	match = rules.Match(RuleInput{URL: "https://example.com/", Description: "A text editors' guide"})
+	assert.Equal(t, []int{3}, match.Matched)
+}
+
+func TestRuleSetCompile(t *testing.T) {
+	assert.Error(t, (&RuleSet{Rules: []Rule{{URL: "(", Tags: []string{"x"}}}}).Compile())
+	assert.Error(t, (&RuleSet{Rules: []Rule{{Name: "empty", Domains: []string{"example.com"}}}}).Compile())
+	assert.NoError(t, DefaultRules().Compile())
+}
+
+func TestLoadRules(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	rules, err := LoadRules()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultRules().Hash(), rules.Hash())
+
+	require.NoError(t, SaveRules(testRules(t)))
+	rules, err = LoadRules()
+	require.NoError(t, err)
+	assert.Equal(t, "Editors", rules.Match(RuleInput{Name: "a text editor"}).Category)
+}
+
+func TestValidatorAppliesRules(t *testing.T) {
+	validator := NewDefaultValidator(DefaultProcessingConfig(), testRules(t))
+
+	item := validator.Clean(RawItem{
+		URL:            "https://js.wiki/",
+		Name:           "Wiki.js",
+		HeadingContext: []string{"Wikis"},
+		Metadata:       map[string]interface{}{"category": "Wikis"},
+	})
+	assert.Equal(t, "Knowledge Bases", item.Category)
+	assert.Contains(t, item.Tags, "wiki")
+
+	// Fallback categories don't replace the list's
+	item = validator.Clean(RawItem{
+		URL:            "https://neovim.io/",
+		Name:           "Neovim",
+		Description:    "A text editor",
+		HeadingContext: []string{"Tools"},
+		Metadata:       map[string]interface{}{"category": "Tools"},
+	})
+	assert.Equal(t, "Tools", item.Category)
+
+	item = validator.Clean(RawItem{URL: "https://neovim.io/", Name: "Neovim", Description: "A text editor", Metadata: map[string]interface{}{}})
+	assert.Equal(t, "Editors", item.Category)
+}
+
+func TestProcessSourceReprocessesWhenRulesChange(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cacheDir := t.TempDir()
+	sourceDir := filepath.Join(cacheDir, "demo")
+	require.NoError(t, os.MkdirAll(sourceDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "wikis.md"), []byte("# Wikis\n\n- [Wiki.js](https://js.wiki/) - A modern wiki\n"), 0644))
+
+	engine := NewProcessingEngine(cacheDir, DefaultProcessingConfig())
+	source := sources.Source{Name: "demo", Path: sourceDir, Type: sources.SourceTypeGit, Enabled: true}
+	require.NoError(t, engine.ProcessSource(source))
+	assert.False(t, engine.NeedsProcessing(source))
+
+	engine.SetRules(testRules(t))
+	assert.True(t, engine.NeedsProcessing(source))
+	require.NoError(t, engine.ProcessSource(source))
+
+	processed, err := engine.storage.Load("demo")
+	require.NoError(t, err)
+	require.Len(t, processed.Items, 1)
+	assert.Equal(t, "Knowledge Bases", processed.Items[0].Category)
+	assert.False(t, engine.NeedsProcessing(source))
+
+	// Other extraction strategies may find other items
+	config := DefaultProcessingConfig()
+	config.ExtractionStrategies = []string{"regex"}
+	engine = NewProcessingEngine(cacheDir, config)
+	engine.SetRules(testRules(t))
+	assert.True(t, engine.NeedsProcessing(source))
+}
@@ -0,0 +1,164 @@
+package preprocessing
+
+import (
+	"time"
+
+	"freectl/internal/sources"
+)
+
+// Stages of processing a source, reported in ProcessingStatus.Stage
+const (
+	StageReading    = "reading"
+	StageExtracting = "extracting"
+	StageChecking   = "checking links"
+	StageSaving     = "saving"
+)
+
+// ProcessingProgress is a snapshot of the sources being processed
+type ProcessingProgress struct {
+	Running     bool               `json:"running"`
+	StartedAt   time.Time          `json:"started_at,omitzero"`
+	CompletedAt *time.Time         `json:"completed_at,omitempty"`
+	Sources     []ProcessingStatus `json:"sources"`
+}
+
+// Fraction returns how much of the run is done, from 0 to 1. Each source
+// counts equally, and a source being processed by how far along it is.
+func (p ProcessingProgress) Fraction() float64 {
+	if len(p.Sources) == 0 {
+		if p.Running || p.StartedAt.IsZero() {
+			return 0
+		}
+		return 1
+	}
+
+	var done float64
+	for _, status := range p.Sources {
+		done += status.Fraction()
+	}
+	return done / float64(len(p.Sources))
+}
+
+// Failed returns the statuses of the sources that failed to process
+func (p ProcessingProgress) Failed() []ProcessingStatus {
+	var failed []ProcessingStatus
+	for _, status := range p.Sources {
+		if status.Status == "error" {
+			failed = append(failed, status)
+		}
+	}
+	return failed
+}
+
+// Done reports whether the source has finished processing, successfully or not
+func (s ProcessingStatus) Done() bool {
+	return s.Status != "pending" && s.Status != "processing"
+}
+
+// Fraction returns how much of the source has been processed, from 0 to 1
+func (s ProcessingStatus) Fraction() float64 {
+	switch {
+	case s.Done():
+		return 1
+	case s.FilesTotal == 0:
+		return 0
+	}
+
+	// Checking links takes as long as extracting every file, or longer
+	fraction := float64(s.FilesProcessed) / float64(s.FilesTotal)
+	if s.CheckLinks {
+		fraction /= 2
+		if s.LinksTotal > 0 {
+			fraction += 0.5 * float64(s.LinksChecked) / float64(s.LinksTotal)
+		}
+	}
+	return min(fraction, 0.99)
+}
+
+// Progress returns the live status of the sources this engine is processing
+// or last processed
+func (pe *ProcessingEngine) Progress() ProcessingProgress {
+	pe.statusMu.Lock()
+	defer pe.statusMu.Unlock()
+
+	progress := ProcessingProgress{
+		Running:   pe.running,
+		StartedAt: pe.runStartedAt,
+		Sources:   make([]ProcessingStatus, 0, len(pe.statusOrder)),
+	}
+	if pe.runCompletedAt != nil {
+		completedAt := *pe.runCompletedAt
+		progress.CompletedAt = &completedAt
+	}
+	for _, name := range pe.statusOrder {
+		progress.Sources = append(progress.Sources, *pe.statuses[name])
+	}
+	return progress
+}
+
+// Updates returns a channel that is closed the next time the progress
+// changes. Take a new channel after each change to keep following it.
+func (pe *ProcessingEngine) Updates() <-chan struct{} {
+	pe.statusMu.Lock()
+	defer pe.statusMu.Unlock()
+
+	if pe.updated == nil {
+		pe.updated = make(chan struct{})
+	}
+	return pe.updated
+}
+
+// setStatus applies update to the live status of a source and notifies
+// those following the progress
+func (pe *ProcessingEngine) setStatus(name string, update func(*ProcessingStatus)) {
+	pe.statusMu.Lock()
+	defer pe.statusMu.Unlock()
+
+	if pe.statuses == nil {
+		pe.statuses = make(map[string]*ProcessingStatus)
+	}
+	status, ok := pe.statuses[name]
+	if !ok {
+		status = &ProcessingStatus{SourceName: name, Status: "pending"}
+		pe.statuses[name] = status
+		pe.statusOrder = append(pe.statusOrder, name)
+	}
+	update(status)
+	pe.notifyLocked()
+}
+
+// beginRun resets the live status for a run processing sourceList
+func (pe *ProcessingEngine) beginRun(sourceList []sources.Source) {
+	pe.statusMu.Lock()
+	defer pe.statusMu.Unlock()
+
+	pe.running = true
+	pe.runStartedAt = time.Now()
+	pe.runCompletedAt = nil
+	pe.statuses = make(map[string]*ProcessingStatus, len(sourceList))
+	pe.statusOrder = nil
+	for _, source := range sourceList {
+		pe.statuses[source.Name] = &ProcessingStatus{SourceName: source.Name, Status: "pending"}
+		pe.statusOrder = append(pe.statusOrder, source.Name)
+	}
+	pe.notifyLocked()
+}
+
+// endRun marks the current run as finished
+func (pe *ProcessingEngine) endRun() {
+	pe.statusMu.Lock()
+	defer pe.statusMu.Unlock()
+
+	now := time.Now()
+	pe.running = false
+	pe.runCompletedAt = &now
+	pe.notifyLocked()
+}
+
+// notifyLocked wakes those waiting on Updates. statusMu must be held.
+func (pe *ProcessingEngine) notifyLocked() {
+	if pe.updated != nil {
+		close(pe.updated)
+	}
+	pe.updated = make(chan struct{})
+}
@@ -0,0 +1,83 @@
+package preprocessing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"freectl/internal/sources"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessAllSourcesTracksProgress(t *testing.T) {
+	cacheDir := t.TempDir()
+	sourceDir := filepath.Join(cacheDir, "demo")
+	require.NoError(t, os.MkdirAll(sourceDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "wikis.md"), []byte("# Wikis\n\n- [Wiki.js](https://js.wiki/) - A modern wiki\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "torrents.md"), []byte("# Torrents\n\n- [qBittorrent](https://qbittorrent.org/) - Torrent client\n"), 0644))
+
+	engine := NewProcessingEngine(cacheDir, DefaultProcessingConfig())
+	updates := engine.Updates()
+	assert.False(t, engine.Progress().Running)
+
+	err := engine.ProcessAllSources([]sources.Source{
+		{Name: "demo", Path: sourceDir, Type: sources.SourceTypeGit, Enabled: true},
+		{Name: "missing", Path: filepath.Join(cacheDir, "missing"), Type: sources.SourceTypeGit, Enabled: true},
+		{Name: "disabled", Path: sourceDir, Type: sources.SourceTypeGit},
+	})
+	assert.ErrorContains(t, err, "missing")
+
+	select {
+	case <-updates:
+	default:
+		t.Fatal("updates weren't signalled")
+	}
+
+	progress := engine.Progress()
+	assert.False(t, progress.Running)
+	assert.False(t, progress.StartedAt.IsZero())
+	require.NotNil(t, progress.CompletedAt)
+	assert.Equal(t, 1.0, progress.Fraction())
+	require.Len(t, progress.Sources, 2)
+
+	demo := progress.Sources[0]
+	assert.Equal(t, "demo", demo.SourceName)
+	assert.Equal(t, "completed", demo.Status)
+	assert.Equal(t, 2, demo.FilesProcessed)
+	assert.Equal(t, 2, demo.FilesTotal)
+	assert.Equal(t, 2, demo.ItemsTotal)
+	assert.NotNil(t, demo.CompletedAt)
+
+	require.Len(t, progress.Failed(), 1)
+	assert.Equal(t, "missing", progress.Failed()[0].SourceName)
+	assert.NotEmpty(t, progress.Failed()[0].Error)
+
+	// Live statuses take the place of the stored ones
+	statuses, err := engine.GetProcessingStatus()
+	require.NoError(t, err)
+	assert.Len(t, statuses, 2)
+}
+
+func TestProcessingStatusFraction(t *testing.T) {
+	tests := []struct {
+		name   string
+		status ProcessingStatus
+		want   float64
+	}{
+		{"pending", ProcessingStatus{Status: "pending"}, 0},
+		{"reading", ProcessingStatus{Status: "processing", Stage: StageReading}, 0},
+		{"extracting", ProcessingStatus{Status: "processing", FilesProcessed: 1, FilesTotal: 4}, 0.25},
+		{"extracted", ProcessingStatus{Status: "processing", FilesProcessed: 4, FilesTotal: 4}, 0.99},
+		{"checking links", ProcessingStatus{Status: "processing", FilesProcessed: 4, FilesTotal: 4, CheckLinks: true, LinksChecked: 1, LinksTotal: 2}, 0.75},
+		{"completed", ProcessingStatus{Status: "completed"}, 1},
+		{"error", ProcessingStatus{Status: "error"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, tt.status.Fraction(), 0.001)
+		})
+	}
+}
@@ -7,8 +7,18 @@ import (
 
 // ProcessedSource represents a fully processed data source
 type ProcessedSource struct {
-	Source SourceMetadata  `json:"source"`
-	Items  []ProcessedItem `json:"items"`
+	Source SourceMetadata       `json:"source"`
+	Items  []ProcessedItem      `json:"items"`
+	Files  map[string]FileState `json:"files,omitempty"` // keyed by path relative to the source directory
+	Rules  string               `json:"rules,omitempty"` // hash of the rules, category overrides and extraction strategies the items were processed with
+}
+
+// FileState records what was extracted from a source file, so the file is
+// only extracted again once its content changes
+type FileState struct {
+	Hash   string   `json:"hash"`  // SHA-256 of the file content
+	Items  int      `json:"items"` // valid items before deduplicating across files
+	Errors []string `json:"errors,omitempty"`
 }
 
 // SourceMetadata contains metadata about the source
@@ -16,17 +26,33 @@ type SourceMetadata = extractors.SourceMetadata
 
 // ProcessedItem represents a single extracted link/item
 type ProcessedItem struct {
-	ID            string       `json:"id"`
-	URL           string       `json:"url"`
-	Name          string       `json:"name"`
-	Description   string       `json:"description"`
-	Category      string       `json:"category"`
-	Subcategory   string       `json:"subcategory,omitempty"`
-	Tags          []string     `json:"tags,omitempty"`
-	SourceContext string       `json:"source_context,omitempty"`
-	RawText       string       `json:"raw_text,omitempty"`
-	ExtractedAt   time.Time    `json:"extracted_at"`
-	Metadata      ItemMetadata `json:"metadata"`
+	ID            string         `json:"id"`
+	URL           string         `json:"url"`
+	Name          string         `json:"name"`
+	Description   string         `json:"description"`
+	Category      string         `json:"category"`
+	Subcategory   string         `json:"subcategory,omitempty"`
+	Tags          []string       `json:"tags,omitempty"`
+	SourceContext string         `json:"source_context,omitempty"`
+	RawText       string         `json:"raw_text,omitempty"`
+	ExtractedAt   time.Time      `json:"extracted_at"`
+	Attributes    ItemAttributes `json:"attributes,omitzero"`
+	Health        LinkHealth     `json:"health,omitzero"` // set once the link has been checked
+	Metadata      ItemMetadata   `json:"metadata"`
+}
+
+// ItemAttributes are facts about a resource that a list states next to its
+// link, such as in the columns of a table or with badges and markers
+type ItemAttributes struct {
+	License       string   `json:"license,omitempty"`
+	Stars         int      `json:"stars,omitempty"`
+	Language      string   `json:"language,omitempty"`
+	Platforms     []string `json:"platforms,omitempty"`
+	OpenSource    bool     `json:"open_source,omitempty"`
+	SourceCodeURL string   `json:"source_code_url,omitempty"`
+	DemoURL       string   `json:"demo_url,omitempty"`
+	Pricing       string   `json:"pricing,omitempty"`  // free, freemium, free trial or paid
+	Featured      bool     `json:"featured,omitempty"` // recommended by the list
 }
 
 // ItemMetadata contains additional metadata about how the item was extracted
@@ -51,12 +77,18 @@ type ExtractionStats = extractors.ExtractionStats
 // ProcessingStatus represents the status of preprocessing operations
 type ProcessingStatus struct {
 	SourceName     string     `json:"source_name"`
-	Status         string     `json:"status"` // "pending", "processing", "completed", "error"
-	StartedAt      time.Time  `json:"started_at"`
+	Status         string     `json:"status"`          // "pending", "processing", "completed", "completed_with_errors", "error"
+	Stage          string     `json:"stage,omitempty"` // one of the Stage constants while processing
+	StartedAt      time.Time  `json:"started_at,omitzero"`
 	CompletedAt    *time.Time `json:"completed_at,omitempty"`
 	Error          string     `json:"error,omitempty"`
 	ItemsProcessed int        `json:"items_processed"`
 	ItemsTotal     int        `json:"items_total"`
+	FilesProcessed int        `json:"files_processed"`
+	FilesTotal     int        `json:"files_total"`
+	CheckLinks     bool       `json:"check_links,omitempty"` // links are checked once the files are extracted
+	LinksChecked   int        `json:"links_checked,omitempty"`
+	LinksTotal     int        `json:"links_total,omitempty"`
 }
 
 // ProcessingConfig contains configuration for the preprocessing pipeline
@@ -69,8 +101,12 @@ type ProcessingConfig struct {
 	EnableAutoCategorization bool     `json:"enable_auto_categorization"`
 	ParallelProcessing       bool     `json:"parallel_processing"`
 	MaxConcurrentSources     int      `json:"max_concurrent_sources"`
-	ValidateURLs             bool     `json:"validate_urls"`
+	ValidateURLs             bool     `json:"validate_urls"` // check the links of processed items, see LinkChecker
 	DeduplicateItems         bool     `json:"deduplicate_items"`
+
+	// CategoryOverrides maps headings to the category path to file them
+	// under, such as "Movies & Shows" to "Media > Video"
+	CategoryOverrides map[string]string `json:"category_overrides,omitempty"`
 }
 
 // DefaultProcessingConfig returns a default processing configuration
@@ -78,7 +114,7 @@ func DefaultProcessingConfig() ProcessingConfig {
 	return ProcessingConfig{
 		AutoProcess:              true,
 		ProcessOnUpdate:          true,
-		ExtractionStrategies:     []string{"structured", "regex", "simple"},
+		ExtractionStrategies:     []string{"table", "structured", "regex", "simple"},
 		UseSearchIndex:           false,
 		MaxDescriptionLength:     500,
 		EnableAutoCategorization: true,
@@ -92,6 +128,9 @@ func DefaultProcessingConfig() ProcessingConfig {
 // Extractor is the interface that all source extractors must implement
 type Extractor = extractors.Extractor
 
+// FileExtractor is an extractor that can process one file at a time
+type FileExtractor = extractors.FileExtractor
+
 // Strategy represents a specific extraction strategy
 type Strategy = extractors.Strategy
 
@@ -8,22 +8,33 @@ import (
 	"strings"
 	"time"
 	"unicode/utf8"
+
+	"freectl/internal/preprocessing/extractors"
 )
 
 // DefaultValidator implements ItemValidator with standard validation and cleaning logic
 type DefaultValidator struct {
-	config   ProcessingConfig
-	urlRegex *regexp.Regexp
+	config     ProcessingConfig
+	rules      *RuleSet
+	categories *CategoryNormalizer
+	urlRegex   *regexp.Regexp
 }
 
-// NewDefaultValidator creates a new default validator
-func NewDefaultValidator(config ProcessingConfig) ItemValidator {
+// NewDefaultValidator creates a new default validator that categorises and
+// tags items with rules, or with DefaultRules when rules is nil
+func NewDefaultValidator(config ProcessingConfig, rules *RuleSet) ItemValidator {
+	if rules == nil {
+		rules = DefaultRules()
+	}
+
 	// Regex for basic URL validation
 	urlRegex := regexp.MustCompile(`^https?://[^\s<>"{}|\\^` + "`" + `\[\]]+$`)
 
 	return &DefaultValidator{
-		config:   config,
-		urlRegex: urlRegex,
+		config:     config,
+		rules:      rules,
+		categories: NewCategoryNormalizer(config.CategoryOverrides),
+		urlRegex:   urlRegex,
 	}
 }
 
@@ -94,24 +105,29 @@ func (dv *DefaultValidator) Clean(item RawItem) ProcessedItem {
 	// Clean description
 	cleanDescription := dv.cleanDescription(item.Description)
 
-	// Extract category from metadata or heading context
-	category := dv.extractCategory(item)
+	// Apply the categorisation and tagging rules
+	match := dv.rules.Match(dv.ruleInput(item))
 
-	// Extract subcategory if possible
-	subcategory := dv.extractSubcategory(item)
+	// Extract category and its parent from rules, metadata or heading context
+	category, subcategory := dv.extractCategory(item, match)
 
 	// Extract tags
-	tags := dv.extractTags(item)
+	tags := dv.extractTags(item, match)
 
 	// Clean source context
 	sourceContext := dv.cleanSourceContext(item.Context)
 
 	// Build metadata
 	metadata := ItemMetadata{
-		ExtractorUsed: "markdown", // This should come from the extraction context
+		ExtractorUsed: "markdown",
 		Confidence:    dv.calculateConfidence(item),
 	}
 
+	// Record the strategy that found the item, if known
+	if strategy, ok := item.Metadata["strategy"].(string); ok && strategy != "" {
+		metadata.ExtractorUsed = strategy
+	}
+
 	// Add file path if available
 	if filePath, ok := item.Metadata["file_path"].(string); ok {
 		metadata.FilePath = filePath
@@ -140,10 +156,34 @@ func (dv *DefaultValidator) Clean(item RawItem) ProcessedItem {
 		SourceContext: sourceContext,
 		RawText:       item.RawText,
 		ExtractedAt:   now,
+		Attributes:    dv.extractAttributes(item),
 		Metadata:      metadata,
 	}
 }
 
+// extractAttributes collects the attributes extraction found for an item
+func (dv *DefaultValidator) extractAttributes(item RawItem) ItemAttributes {
+	var attributes ItemAttributes
+	if license, ok := item.Metadata["license"].(string); ok {
+		attributes.License = strings.TrimSpace(license)
+	}
+	if stars, ok := item.Metadata["stars"].(int); ok {
+		attributes.Stars = stars
+	}
+	if language, ok := item.Metadata["language"].(string); ok {
+		attributes.Language = strings.TrimSpace(language)
+	}
+	if platforms, ok := item.Metadata["platforms"].([]string); ok {
+		attributes.Platforms = platforms
+	}
+	attributes.OpenSource, _ = item.Metadata["open_source"].(bool)
+	attributes.SourceCodeURL, _ = item.Metadata["source_code_url"].(string)
+	attributes.DemoURL, _ = item.Metadata["demo_url"].(string)
+	attributes.Pricing, _ = item.Metadata["pricing"].(string)
+	attributes.Featured, _ = item.Metadata["featured"].(bool)
+	return attributes
+}
+
 // generateID generates a unique ID for an item based on its content
 func (dv *DefaultValidator) generateID(item RawItem) string {
 	data := fmt.Sprintf("%s|%s|%s", item.URL, item.Name, item.Description)
@@ -272,41 +312,49 @@ func (dv *DefaultValidator) removeMarkdownFormatting(text string) string {
 	return text
 }
 
-// extractCategory determines the category for an item
-func (dv *DefaultValidator) extractCategory(item RawItem) string {
-	// Try to get category from metadata first
-	if category, ok := item.Metadata["category"].(string); ok && category != "" {
-		return dv.normalizeCategory(category)
+// ruleInput returns what the rules match an item against
+func (dv *DefaultValidator) ruleInput(item RawItem) RuleInput {
+	input := RuleInput{URL: item.URL, Name: item.Name, Description: item.Description, Headings: item.HeadingContext}
+	if hierarchy, ok := item.Metadata["hierarchy"].([]string); ok {
+		input.Headings = hierarchy
 	}
+	return input
+}
 
-	// Try to get from heading context
-	if len(item.HeadingContext) > 0 {
-		// Use the last (most specific) heading
-		return dv.normalizeCategory(item.HeadingContext[len(item.HeadingContext)-1])
+// extractCategory determines the category for an item and the category it
+// belongs to, if any
+func (dv *DefaultValidator) extractCategory(item RawItem, match RuleMatch) (string, string) {
+	// A rule's category replaces the list's, unless it is a fallback
+	if match.Category != "" && !match.Fallback {
+		return categoryFromPath(splitCategoryPath(match.Category))
 	}
 
-	// Try to infer from URL or name if auto-categorization is enabled
-	if dv.config.EnableAutoCategorization {
-		if category := dv.inferCategoryFromContent(item); category != "" {
-			return category
-		}
+	// Use the most specific heading, from metadata or the heading context
+	var heading string
+	if category, ok := item.Metadata["category"].(string); ok && category != "" {
+		heading = category
+	} else if len(item.HeadingContext) > 0 {
+		heading = item.HeadingContext[len(item.HeadingContext)-1]
 	}
 
-	return "Uncategorized"
-}
+	if category, parent, ok := dv.categories.Normalize(heading); category != "" {
+		// Headings outside the category tree belong to the heading above them
+		if !ok && len(item.HeadingContext) >= 2 {
+			parent, _, _ = dv.categories.Normalize(item.HeadingContext[len(item.HeadingContext)-2])
+		}
+		return category, parent
+	}
 
-// extractSubcategory determines the subcategory for an item
-func (dv *DefaultValidator) extractSubcategory(item RawItem) string {
-	// If we have multiple levels in the heading hierarchy, use the second-to-last as subcategory
-	if len(item.HeadingContext) >= 2 {
-		return dv.normalizeCategory(item.HeadingContext[len(item.HeadingContext)-2])
+	// Fall back to the rules if auto-categorization is enabled
+	if dv.config.EnableAutoCategorization && match.Category != "" {
+		return categoryFromPath(splitCategoryPath(match.Category))
 	}
 
-	return ""
+	return "Uncategorized", ""
 }
 
 // extractTags extracts tags from the item context and content
-func (dv *DefaultValidator) extractTags(item RawItem) []string {
+func (dv *DefaultValidator) extractTags(item RawItem, match RuleMatch) []string {
 	var tags []string
 
 	// Extract from URL domain
@@ -328,70 +376,31 @@ func (dv *DefaultValidator) extractTags(item RawItem) []string {
 		}
 	}
 
-	// Extract common keywords from name and description
-	content := strings.ToLower(item.Name + " " + item.Description)
-	commonTags := []string{
-		"free", "open-source", "tool", "library", "framework", "api", "service",
-		"tutorial", "guide", "documentation", "blog", "article", "video",
-		"github", "python", "javascript", "go", "rust", "java",
+	// Languages and platforms stated by the list
+	if language, ok := item.Metadata["language"].(string); ok && language != "" {
+		tags = append(tags, strings.ToLower(language))
 	}
-
-	for _, tag := range commonTags {
-		if strings.Contains(content, tag) {
-			tags = append(tags, tag)
+	if platforms, ok := item.Metadata["platforms"].([]string); ok {
+		for _, platform := range platforms {
+			tags = append(tags, strings.ToLower(platform))
 		}
 	}
 
-	// Remove duplicates and limit to reasonable number
-	tags = dv.deduplicateAndLimitTags(tags, 10)
-
-	return tags
-}
-
-// inferCategoryFromContent attempts to infer category from URL and content
-func (dv *DefaultValidator) inferCategoryFromContent(item RawItem) string {
-	content := strings.ToLower(item.Name + " " + item.Description + " " + item.URL)
-
-	// Define category keywords
-	categories := map[string][]string{
-		"Development": {"api", "library", "framework", "tool", "github", "code", "programming", "developer"},
-		"Media":       {"video", "movie", "music", "streaming", "download", "torrent"},
-		"Education":   {"tutorial", "course", "learn", "education", "training", "guide"},
-		"News":        {"news", "blog", "article", "journalism", "magazine"},
-		"Security":    {"security", "privacy", "vpn", "encryption", "hack", "cybersecurity"},
-		"Gaming":      {"game", "gaming", "steam", "playstation", "xbox", "nintendo"},
-		"Social":      {"social", "forum", "community", "discord", "reddit", "twitter"},
-	}
-
-	for category, keywords := range categories {
-		for _, keyword := range keywords {
-			if strings.Contains(content, keyword) {
-				return category
-			}
-		}
+	// Annotations such as badges and "(Paid)" markers
+	if openSource, _ := item.Metadata["open_source"].(bool); openSource {
+		tags = append(tags, "open-source")
 	}
-
-	return ""
-}
-
-// normalizeCategory normalizes category names
-func (dv *DefaultValidator) normalizeCategory(category string) string {
-	category = strings.TrimSpace(category)
-
-	// Remove common prefixes/suffixes
-	category = strings.TrimSuffix(category, "s") // Remove plural
-
-	// Capitalize first letter
-	if len(category) > 0 {
-		category = strings.ToUpper(category[:1]) + strings.ToLower(category[1:])
+	if pricing, ok := item.Metadata["pricing"].(string); ok && pricing != "" {
+		tags = append(tags, strings.ReplaceAll(pricing, " ", "-"))
 	}
 
-	// Handle empty categories
-	if category == "" {
-		return "Uncategorized"
-	}
+	// Tags from the rules
+	tags = append(tags, match.Tags...)
+
+	// Remove duplicates and limit to reasonable number
+	tags = dv.deduplicateAndLimitTags(tags, 10)
 
-	return category
+	return tags
 }
 
 // cleanSourceContext cleans the source context string
@@ -413,36 +422,7 @@ func (dv *DefaultValidator) cleanSourceContext(context string) string {
 
 // calculateConfidence calculates a confidence score for the extraction
 func (dv *DefaultValidator) calculateConfidence(item RawItem) float64 {
-	confidence := 0.5 // Base confidence
-
-	// Higher confidence if we have a good name
-	if item.Name != "" && len(item.Name) > 3 && item.Name != item.URL {
-		confidence += 0.2
-	}
-
-	// Higher confidence if we have description
-	if item.Description != "" && len(item.Description) > 10 {
-		confidence += 0.15
-	}
-
-	// Higher confidence if we have heading context
-	if len(item.HeadingContext) > 0 {
-		confidence += 0.1
-	}
-
-	// Higher confidence if URL looks valid
-	if parsedURL, err := url.Parse(item.URL); err == nil {
-		if parsedURL.Host != "" && !strings.Contains(parsedURL.Host, "localhost") {
-			confidence += 0.05
-		}
-	}
-
-	// Cap at 1.0
-	if confidence > 1.0 {
-		confidence = 1.0
-	}
-
-	return confidence
+	return extractors.Confidence(item)
 }
 
 // deduplicateAndLimitTags removes duplicates and limits the number of tags
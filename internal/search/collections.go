@@ -0,0 +1,233 @@
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Collection is a named group of favorites, such as a per-project toolbox.
+// A favorite can be in any number of collections.
+type Collection struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at,omitzero"`
+}
+
+// CollectionSummary is a collection with the number of favorites in it
+type CollectionSummary struct {
+	Collection
+	Count int `json:"count"`
+}
+
+func getCollectionsPath() (string, error) {
+	return configPath("collections.json")
+}
+
+// LoadCollections loads the collections that have been created
+func LoadCollections() ([]Collection, error) {
+	path, err := getCollectionsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Collection{}, nil
+		}
+		return nil, fmt.Errorf("failed to read collections file: %w", err)
+	}
+
+	var collections []Collection
+	if err := json.Unmarshal(data, &collections); err != nil {
+		return nil, fmt.Errorf("failed to parse collections file: %w", err)
+	}
+
+	return collections, nil
+}
+
+// SaveCollections replaces the saved collections
+func SaveCollections(collections []Collection) error {
+	path, err := getCollectionsPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(collections, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal collections: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write collections file: %w", err)
+	}
+
+	return nil
+}
+
+// ListCollections returns every collection with its favorite count, sorted
+// by name. Collections that favorites refer to but that were never created
+// are included too.
+func ListCollections() ([]CollectionSummary, error) {
+	collections, err := LoadCollections()
+	if err != nil {
+		return nil, err
+	}
+	favorites, err := LoadFavorites()
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make(map[string]*CollectionSummary)
+	for _, c := range collections {
+		summaries[strings.ToLower(c.Name)] = &CollectionSummary{Collection: c}
+	}
+	for _, f := range favorites {
+		for _, name := range f.Collections {
+			key := strings.ToLower(name)
+			if summaries[key] == nil {
+				summaries[key] = &CollectionSummary{Collection: Collection{Name: name}}
+			}
+			summaries[key].Count++
+		}
+	}
+
+	result := make([]CollectionSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		result = append(result, *summary)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return strings.ToLower(result[i].Name) < strings.ToLower(result[j].Name)
+	})
+
+	return result, nil
+}
+
+// CreateCollection creates an empty collection
+func CreateCollection(name, description string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("collection name is required")
+	}
+
+	collections, err := LoadCollections()
+	if err != nil {
+		return err
+	}
+
+	if slices.ContainsFunc(collections, func(c Collection) bool { return strings.EqualFold(c.Name, name) }) {
+		return fmt.Errorf("collection '%s' already exists", name)
+	}
+
+	collections = append(collections, Collection{Name: name, Description: description, CreatedAt: time.Now()})
+	return SaveCollections(collections)
+}
+
+// RenameCollection renames a collection, moving its favorites along with it
+func RenameCollection(oldName, newName string) error {
+	newName = strings.TrimSpace(newName)
+	if newName == "" {
+		return fmt.Errorf("collection name is required")
+	}
+
+	collections, err := LoadCollections()
+	if err != nil {
+		return err
+	}
+	favorites, err := LoadFavorites()
+	if err != nil {
+		return err
+	}
+
+	// Renaming onto another collection would merge the two
+	if !strings.EqualFold(oldName, newName) && collectionExists(collections, favorites, newName) {
+		return fmt.Errorf("collection '%s' already exists", newName)
+	}
+
+	found := false
+	for i := range collections {
+		if strings.EqualFold(collections[i].Name, oldName) {
+			collections[i].Name = newName
+			found = true
+		}
+	}
+	for i := range favorites {
+		for j, name := range favorites[i].Collections {
+			if strings.EqualFold(name, oldName) {
+				favorites[i].Collections[j] = newName
+				found = true
+			}
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("collection '%s' not found", oldName)
+	}
+
+	if err := SaveCollections(collections); err != nil {
+		return err
+	}
+	return SaveFavorites(favorites)
+}
+
+// DeleteCollection deletes a collection. Its favorites are kept, they are
+// only taken out of the collection.
+func DeleteCollection(name string) error {
+	collections, err := LoadCollections()
+	if err != nil {
+		return err
+	}
+	favorites, err := LoadFavorites()
+	if err != nil {
+		return err
+	}
+
+	if !collectionExists(collections, favorites, name) {
+		return fmt.Errorf("collection '%s' not found", name)
+	}
+
+	collections = slices.DeleteFunc(collections, func(c Collection) bool { return strings.EqualFold(c.Name, name) })
+	for i := range favorites {
+		favorites[i].Collections = slices.DeleteFunc(favorites[i].Collections, func(c string) bool { return strings.EqualFold(c, name) })
+	}
+
+	if err := SaveCollections(collections); err != nil {
+		return err
+	}
+	return SaveFavorites(favorites)
+}
+
+// collectionExists reports whether a collection was created or has
+// favorites in it
+func collectionExists(collections []Collection, favorites []Favorite, name string) bool {
+	if slices.ContainsFunc(collections, func(c Collection) bool { return strings.EqualFold(c.Name, name) }) {
+		return true
+	}
+	return slices.ContainsFunc(favorites, func(f Favorite) bool { return containsFold(f.Collections, name) })
+}
+
+// AddToCollection puts a favorite in a collection
+func AddToCollection(link, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("collection name is required")
+	}
+
+	return UpdateFavorite(link, func(f *Favorite) {
+		if !containsFold(f.Collections, name) {
+			f.Collections = append(f.Collections, name)
+		}
+	})
+}
+
+// RemoveFromCollection takes a favorite out of a collection
+func RemoveFromCollection(link, name string) error {
+	return UpdateFavorite(link, func(f *Favorite) {
+		f.Collections = slices.DeleteFunc(f.Collections, func(c string) bool { return strings.EqualFold(c, name) })
+	})
+}
@@ -0,0 +1,118 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"freectl/internal/settings"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollections(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	require.NoError(t, AddFavorite(Favorite{Link: "https://koala.com/", Name: "Koala", Tags: []string{"sleepy"}}))
+	require.NoError(t, AddFavorite(Favorite{Link: "https://kangaroo.com/", Name: "Kangaroo", Collections: []string{"Marsupials"}}))
+	require.NoError(t, AddFavorite(Favorite{Link: "https://wombat.com/", Name: "Wombat"}))
+	require.NoError(t, AddFavorite(Favorite{Link: "http://www.wombat.com/?utm_source=list", Name: "Wombat again"}))
+
+	isFavorite, err := IsFavorite("https://WOMBAT.com")
+	require.NoError(t, err)
+	assert.True(t, isFavorite)
+
+	require.NoError(t, CreateCollection("Toolbox", "Things for the project"))
+	assert.Error(t, CreateCollection("toolbox", ""))
+	require.NoError(t, AddToCollection("https://koala.com/", "Marsupials"))
+	require.NoError(t, AddToCollection("https://koala.com/", "marsupials"))
+	assert.Error(t, AddToCollection("https://missing.com/", "Marsupials"))
+
+	summaries, err := ListCollections()
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, "Marsupials", summaries[0].Name)
+	assert.Equal(t, 2, summaries[0].Count)
+	assert.Equal(t, "Toolbox", summaries[1].Name)
+	assert.Equal(t, "Things for the project", summaries[1].Description)
+	assert.Equal(t, 0, summaries[1].Count)
+
+	assert.ErrorContains(t, RenameCollection("Marsupials", "toolbox"), "already exists")
+	assert.ErrorContains(t, RenameCollection("Missing", "Elsewhere"), "not found")
+	require.NoError(t, RenameCollection("Marsupials", "marsupials"))
+	require.NoError(t, RenameCollection("marsupials", "Pouches"))
+	favorites, err := LoadFavorites()
+	require.NoError(t, err)
+	assert.Len(t, FilterFavorites(favorites, FavoriteFilter{Collection: "pouches"}), 2)
+	assert.Len(t, FilterFavorites(favorites, FavoriteFilter{Collection: "Pouches", Tag: "sleepy"}), 1)
+	assert.False(t, favorites[0].CreatedAt.IsZero())
+
+	require.NoError(t, DeleteCollection("Pouches"))
+	assert.ErrorContains(t, DeleteCollection("Pouches"), "not found")
+	favorites, err = LoadFavorites()
+	require.NoError(t, err)
+	assert.Empty(t, FilterFavorites(favorites, FavoriteFilter{Collection: "Pouches"}))
+	assert.Len(t, favorites, 3)
+}
+
+func TestMoveFavorite(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	for _, link := range []string{"https://a.com/", "https://b.com/", "https://c.com/"} {
+		require.NoError(t, AddFavorite(Favorite{Link: link}))
+	}
+
+	require.NoError(t, MoveFavorite("https://c.com/", 0))
+	require.NoError(t, MoveFavorite("https://a.com/", 10))
+	assert.Error(t, MoveFavorite("https://missing.com/", 0))
+
+	favorites, err := LoadFavorites()
+	require.NoError(t, err)
+	var links []string
+	for _, f := range favorites {
+		links = append(links, f.Link)
+	}
+	assert.Equal(t, []string{"https://c.com/", "https://b.com/", "https://a.com/"}, links)
+}
+
+func TestFavoritesSearcher(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	require.NoError(t, AddFavorite(Favorite{Link: "https://js.wiki/", Name: "Wiki.js", Description: "A modern wiki", Collections: []string{"Docs"}}))
+	require.NoError(t, AddFavorite(Favorite{Link: "https://koala.com/", Name: "Koala", Notes: "the wiki we tried first"}))
+	require.NoError(t, AddFavorite(Favorite{Link: "https://kangaroo.com/", Name: "Kangaroo", Repository: "awesome-animals"}))
+
+	searcher := NewFavoritesSearcher(settings.Settings{}, FavoriteFilter{})
+	resp, err := searcher.Search(context.Background(), Request{Query: "wiki"})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 2)
+	assert.Equal(t, "https://js.wiki/", resp.Results[0].URL)
+	assert.Equal(t, FavoritesSource, resp.Results[0].Source)
+	assert.Equal(t, "https://koala.com/", resp.Results[1].URL)
+
+	resp, err = searcher.Search(context.Background(), Request{Query: "kangaroo"})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 1)
+	assert.Equal(t, "awesome-animals", resp.Results[0].Source)
+
+	// Collections are searched and faceted apart from tags
+	resp, err = searcher.Search(context.Background(), Request{Query: "docs"})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 1)
+	assert.Equal(t, "https://js.wiki/", resp.Results[0].URL)
+	assert.Empty(t, resp.Results[0].Tags)
+	assert.Equal(t, []string{"Docs"}, resp.Results[0].Collections)
+	assert.Empty(t, resp.Facets[FacetTag])
+	assert.Equal(t, []FacetCount{{Value: "Docs", Count: 1}}, resp.Facets[FacetCollection])
+
+	resp, err = searcher.Search(context.Background(), Request{Query: "wiki", Filters: SearchFilters{Collections: []string{"docs"}}})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 1)
+	assert.Equal(t, "https://js.wiki/", resp.Results[0].URL)
+
+	searcher = NewFavoritesSearcher(settings.Settings{}, FavoriteFilter{Collection: "docs"})
+	resp, err = searcher.Search(context.Background(), Request{Query: "wiki"})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 1)
+	assert.Equal(t, "https://js.wiki/", resp.Results[0].URL)
+}
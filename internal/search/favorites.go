@@ -1,21 +1,64 @@
 package search
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"time"
+
+	"freectl/internal/common"
+	"freectl/internal/settings"
 )
 
+// Favorite is a saved resource. Favorites are kept in the order the user
+// arranged them, with new ones added at the end.
 type Favorite struct {
-	Link        string `json:"link"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Category    string `json:"category"`
-	Repository  string `json:"repository"`
+	Link        string    `json:"link"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Category    string    `json:"category"`
+	Repository  string    `json:"repository"`
+	Collections []string  `json:"collections,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	Notes       string    `json:"notes,omitempty"`
+	CreatedAt   time.Time `json:"created_at,omitzero"`
 }
 
-func getFavoritesPath() (string, error) {
+// FavoriteFilter narrows favorites down to a collection and/or tag
+type FavoriteFilter struct {
+	Collection string
+	Tag        string
+}
+
+// Matches reports whether a favorite is in the filter's collection and has
+// its tag, ignoring case
+func (f FavoriteFilter) Matches(favorite Favorite) bool {
+	if f.Collection != "" && !containsFold(favorite.Collections, f.Collection) {
+		return false
+	}
+	if f.Tag != "" && !containsFold(favorite.Tags, f.Tag) {
+		return false
+	}
+	return true
+}
+
+// FilterFavorites returns the favorites matching the filter, in order
+func FilterFavorites(favorites []Favorite, filter FavoriteFilter) []Favorite {
+	matches := []Favorite{}
+	for _, f := range favorites {
+		if filter.Matches(f) {
+			matches = append(matches, f)
+		}
+	}
+	return matches
+}
+
+// configPath returns the path of a file in the freectl config directory,
+// creating the directory if needed
+func configPath(name string) (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", fmt.Errorf("failed to get home directory: %w", err)
@@ -24,7 +67,11 @@ func getFavoritesPath() (string, error) {
 	if err := os.MkdirAll(configDir, 0755); err != nil {
 		return "", fmt.Errorf("failed to create config directory: %w", err)
 	}
-	return filepath.Join(configDir, "favourites.json"), nil
+	return filepath.Join(configDir, name), nil
+}
+
+func getFavoritesPath() (string, error) {
+	return configPath("favourites.json")
 }
 
 func LoadFavorites() ([]Favorite, error) {
@@ -75,15 +122,59 @@ func AddFavorite(favorite Favorite) error {
 
 	// Check if already exists
 	for _, f := range favorites {
-		if f.Link == favorite.Link {
+		if sameLink(f.Link, favorite.Link) {
 			return nil // Already exists
 		}
 	}
 
+	if favorite.CreatedAt.IsZero() {
+		favorite.CreatedAt = time.Now()
+	}
+
 	favorites = append(favorites, favorite)
 	return SaveFavorites(favorites)
 }
 
+// UpdateFavorite applies update to the favorite with the given link
+func UpdateFavorite(link string, update func(*Favorite)) error {
+	favorites, err := LoadFavorites()
+	if err != nil {
+		return err
+	}
+
+	for i := range favorites {
+		if sameLink(favorites[i].Link, link) {
+			link = favorites[i].Link
+			update(&favorites[i])
+			favorites[i].Link = link
+			return SaveFavorites(favorites)
+		}
+	}
+
+	return fmt.Errorf("favorite '%s' not found", link)
+}
+
+// MoveFavorite moves the favorite with the given link to position, counted
+// from 0. Positions past the end move it to the end.
+func MoveFavorite(link string, position int) error {
+	favorites, err := LoadFavorites()
+	if err != nil {
+		return err
+	}
+
+	index := slices.IndexFunc(favorites, func(f Favorite) bool { return sameLink(f.Link, link) })
+	if index == -1 {
+		return fmt.Errorf("favorite '%s' not found", link)
+	}
+
+	favorite := favorites[index]
+	favorites = slices.Delete(favorites, index, index+1)
+	position = max(0, min(position, len(favorites)))
+	favorites = slices.Insert(favorites, position, favorite)
+
+	return SaveFavorites(favorites)
+}
+
 func RemoveFavorite(favorite Favorite) error {
 	favorites, err := LoadFavorites()
 	if err != nil {
@@ -92,7 +183,7 @@ func RemoveFavorite(favorite Favorite) error {
 
 	// Remove the favorite
 	for i, f := range favorites {
-		if f.Link == favorite.Link {
+		if sameLink(f.Link, favorite.Link) {
 			favorites = append(favorites[:i], favorites[i+1:]...)
 			break
 		}
@@ -108,10 +199,76 @@ func IsFavorite(link string) (bool, error) {
 	}
 
 	for _, f := range favorites {
-		if f.Link == link {
+		if sameLink(f.Link, link) {
 			return true, nil
 		}
 	}
 
 	return false, nil
 }
+
+// sameLink reports whether two links are to the same page, so a favorite
+// isn't added twice through variants of its URL
+func sameLink(a, b string) bool {
+	return a == b || common.CanonicalURL(a) == common.CanonicalURL(b)
+}
+
+// FavoritesSource is the source name favorites have in search results
+const FavoritesSource = "favorites"
+
+// FavoritesSearcher searches favorites with the same fuzzy matching, filters,
+// facets and sorting as the other searchers
+type FavoritesSearcher struct {
+	settings settings.Settings
+	filter   FavoriteFilter
+}
+
+// NewFavoritesSearcher creates a searcher over the favorites matching filter
+func NewFavoritesSearcher(s settings.Settings, filter FavoriteFilter) *FavoritesSearcher {
+	return &FavoritesSearcher{settings: s, filter: filter}
+}
+
+// Name returns the name of this searcher
+func (fs *FavoritesSearcher) Name() string {
+	return FavoritesSource
+}
+
+// Search runs req against the favorites
+func (fs *FavoritesSearcher) Search(ctx context.Context, req Request) (*Response, error) {
+	favorites, err := LoadFavorites()
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	items := favoriteItems(FilterFavorites(favorites, fs.filter))
+	results := performFuzzySearch(req.Query, items, fs.settings)
+	return buildResponse(results, req, fs.settings), nil
+}
+
+// favoriteItems converts favorites for searching. Collections are searched
+// along with the tags but faceted on their own.
+func favoriteItems(favorites []Favorite) []SearchableItem {
+	items := make([]SearchableItem, 0, len(favorites))
+	for _, f := range favorites {
+		source := FavoritesSource
+		if f.Repository != "" {
+			source = f.Repository
+		}
+
+		items = append(items, SearchableItem{
+			URL:         f.Link,
+			Name:        f.Name,
+			Description: f.Description,
+			Category:    f.Category,
+			Source:      source,
+			SourceType:  FavoritesSource,
+			Tags:        f.Tags,
+			Notes:       f.Notes,
+			Collections: f.Collections,
+		})
+	}
+	return items
+}
@@ -0,0 +1,399 @@
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"freectl/internal/common"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// FavoritesFormat is a file format favorites can be exported to and
+// imported from
+type FavoritesFormat string
+
+// Supported favorites formats
+const (
+	FavoritesHTML     FavoritesFormat = "html"
+	FavoritesMarkdown FavoritesFormat = "markdown"
+	FavoritesJSON     FavoritesFormat = "json"
+	FavoritesAwesome  FavoritesFormat = "awesome"
+)
+
+// Ways of grouping the sections of an awesome list
+const (
+	GroupByCollection = "collection"
+	GroupByCategory   = "category"
+)
+
+// AwesomeList describes the awesome-list markdown generated from favorites
+type AwesomeList struct {
+	Title       string
+	Description string
+	GroupBy     string // GroupByCollection or GroupByCategory, defaults to collection
+}
+
+// uncategorized is the heading used for favorites without a category
+const uncategorized = "Uncategorized"
+
+// ParseFavoritesFormat validates a favorites format name
+func ParseFavoritesFormat(name string) (FavoritesFormat, error) {
+	switch strings.ToLower(name) {
+	case "html", "htm", "netscape", "bookmarks":
+		return FavoritesHTML, nil
+	case "markdown", "md":
+		return FavoritesMarkdown, nil
+	case "json":
+		return FavoritesJSON, nil
+	case "awesome", "awesome-list":
+		return FavoritesAwesome, nil
+	}
+	return "", fmt.Errorf("unknown favorites format '%s', expected html, markdown, json or awesome", name)
+}
+
+// FavoritesFormatFromPath guesses the format of a favorites file from its
+// extension
+func FavoritesFormatFromPath(path string) (FavoritesFormat, error) {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+	if ext == "" {
+		return "", fmt.Errorf("cannot tell the format of '%s', pass it explicitly", path)
+	}
+	return ParseFavoritesFormat(ext)
+}
+
+// ExportFavorites writes favorites in the given format. HTML is the Netscape
+// bookmark file format understood by browsers, and both HTML and markdown
+// group favorites by category.
+func ExportFavorites(w io.Writer, favorites []Favorite, format FavoritesFormat) error {
+	switch format {
+	case FavoritesJSON:
+		if favorites == nil {
+			favorites = []Favorite{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(favorites)
+
+	case FavoritesMarkdown:
+		fmt.Fprintln(w, "# Favorites")
+		for _, group := range groupByCategory(favorites) {
+			fmt.Fprintf(w, "\n## %s\n\n", group.category)
+			for _, f := range group.favorites {
+				line := fmt.Sprintf("- [%s](%s)", escapeLinkText(favoriteName(f)), f.Link)
+				if f.Description != "" {
+					line += " - " + strings.Join(strings.Fields(f.Description), " ")
+				}
+				fmt.Fprintln(w, line)
+			}
+		}
+		return nil
+
+	case FavoritesHTML:
+		fmt.Fprintln(w, "<!DOCTYPE NETSCAPE-Bookmark-file-1>")
+		fmt.Fprintln(w, `<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">`)
+		fmt.Fprintln(w, "<TITLE>Bookmarks</TITLE>")
+		fmt.Fprintln(w, "<H1>Bookmarks</H1>")
+		fmt.Fprintln(w, "<DL><p>")
+		for _, group := range groupByCategory(favorites) {
+			fmt.Fprintf(w, "    <DT><H3>%s</H3>\n", html.EscapeString(group.category))
+			fmt.Fprintln(w, "    <DL><p>")
+			for _, f := range group.favorites {
+				attrs := fmt.Sprintf(`HREF="%s"`, html.EscapeString(f.Link))
+				if !f.CreatedAt.IsZero() {
+					attrs += fmt.Sprintf(` ADD_DATE="%d"`, f.CreatedAt.Unix())
+				}
+				if len(f.Tags) > 0 {
+					attrs += fmt.Sprintf(` TAGS="%s"`, html.EscapeString(strings.Join(f.Tags, ",")))
+				}
+				fmt.Fprintf(w, "        <DT><A %s>%s</A>\n", attrs, html.EscapeString(favoriteName(f)))
+				if f.Description != "" {
+					fmt.Fprintf(w, "        <DD>%s\n", html.EscapeString(f.Description))
+				}
+			}
+			fmt.Fprintln(w, "    </DL><p>")
+		}
+		fmt.Fprintln(w, "</DL><p>")
+		return nil
+
+	case FavoritesAwesome:
+		return ExportAwesomeList(w, favorites, AwesomeList{})
+	}
+
+	return fmt.Errorf("unknown favorites format '%s'", format)
+}
+
+// ExportAwesomeList writes favorites as an awesome list: a markdown file with
+// a table of contents and a section per collection or category. Committed to
+// a git repository, it can be added back as a git source, so teammates can
+// search what was curated.
+func ExportAwesomeList(w io.Writer, favorites []Favorite, list AwesomeList) error {
+	if list.Title == "" {
+		list.Title = "Awesome Favorites"
+	}
+	if list.GroupBy == "" {
+		list.GroupBy = GroupByCollection
+	}
+
+	var groups []categoryGroup
+	switch list.GroupBy {
+	case GroupByCollection:
+		groups = groupByCollection(favorites)
+	case GroupByCategory:
+		groups = groupByCategory(favorites)
+	default:
+		return fmt.Errorf("unknown grouping '%s', expected %s or %s", list.GroupBy, GroupByCollection, GroupByCategory)
+	}
+
+	fmt.Fprintf(w, "# %s\n\n", list.Title)
+	if list.Description != "" {
+		fmt.Fprintf(w, "> %s\n\n", list.Description)
+	}
+
+	fmt.Fprintln(w, "## Contents")
+	fmt.Fprintln(w)
+	for _, group := range groups {
+		fmt.Fprintf(w, "- [%s](#%s)\n", escapeLinkText(group.category), headingAnchor(group.category))
+	}
+
+	for _, group := range groups {
+		fmt.Fprintf(w, "\n## %s\n\n", group.category)
+		for _, f := range group.favorites {
+			line := fmt.Sprintf("- [%s](%s)", escapeLinkText(favoriteName(f)), f.Link)
+			if description := strings.Join(strings.Fields(f.Description), " "); description != "" {
+				if !strings.HasSuffix(description, ".") {
+					description += "."
+				}
+				line += " - " + description
+			}
+			fmt.Fprintln(w, line)
+		}
+	}
+
+	return nil
+}
+
+// ImportFavorites reads favorites in the given format
+func ImportFavorites(r io.Reader, format FavoritesFormat) ([]Favorite, error) {
+	switch format {
+	case FavoritesJSON:
+		var favorites []Favorite
+		if err := json.NewDecoder(r).Decode(&favorites); err != nil {
+			return nil, fmt.Errorf("failed to parse favorites: %w", err)
+		}
+		return favorites, nil
+
+	case FavoritesMarkdown, FavoritesAwesome:
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read favorites: %w", err)
+		}
+		return parseMarkdownFavorites(string(data)), nil
+
+	case FavoritesHTML:
+		doc, err := goquery.NewDocumentFromReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse bookmarks: %w", err)
+		}
+		return parseBookmarkFavorites(doc), nil
+	}
+
+	return nil, fmt.Errorf("unknown favorites format '%s'", format)
+}
+
+// MergeFavorites adds the imported favorites that aren't already in
+// favorites, returning the merged list and how many were added
+func MergeFavorites(favorites, imported []Favorite) ([]Favorite, int) {
+	seen := make(map[string]bool, len(favorites))
+	for _, f := range favorites {
+		seen[common.CanonicalURL(f.Link)] = true
+	}
+
+	added := 0
+	for _, f := range imported {
+		key := common.CanonicalURL(f.Link)
+		if f.Link == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		favorites = append(favorites, f)
+		added++
+	}
+	return favorites, added
+}
+
+// parseMarkdownFavorites reads the links in list items, using the nearest
+// heading above each as its category
+func parseMarkdownFavorites(content string) []Favorite {
+	var favorites []Favorite
+	category := ""
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			heading := common.CleanCategory(strings.TrimLeft(line, "# "))
+			// The document title and table of contents aren't categories
+			if !strings.HasPrefix(line, "# ") && heading != "Contents" {
+				category = heading
+			}
+			continue
+		}
+
+		if !strings.HasPrefix(line, "- ") && !strings.HasPrefix(line, "* ") {
+			continue
+		}
+
+		name, link := common.ExtractMarkdownLink(line)
+		if link == "" || strings.HasPrefix(link, "#") {
+			continue
+		}
+
+		var description string
+		if idx := strings.Index(line, "("+link+")"); idx != -1 {
+			description = common.CleanDescription(line[idx+len(link)+2:])
+		}
+
+		favorites = append(favorites, Favorite{
+			Link:        link,
+			Name:        name,
+			Description: description,
+			Category:    favoriteCategory(category),
+		})
+	}
+
+	return favorites
+}
+
+// parseBookmarkFavorites reads the links in a Netscape bookmark file, using
+// the folder each is in as its category
+func parseBookmarkFavorites(doc *goquery.Document) []Favorite {
+	var favorites []Favorite
+
+	doc.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		link, _ := a.Attr("href")
+		if !strings.HasPrefix(link, "http://") && !strings.HasPrefix(link, "https://") {
+			return
+		}
+
+		// Folders are a <DT><H3> whose <DL> holds the bookmarks
+		category := a.Closest("dl").Parent().ChildrenFiltered("h3").First().Text()
+
+		// Descriptions follow the bookmark's <DT> in a <DD>
+		description := a.Closest("dt").Next().Filter("dd").Text()
+
+		favorite := Favorite{
+			Link:        link,
+			Name:        strings.TrimSpace(a.Text()),
+			Description: strings.Join(strings.Fields(description), " "),
+			Category:    favoriteCategory(strings.TrimSpace(category)),
+		}
+		if tags, ok := a.Attr("tags"); ok && tags != "" {
+			favorite.Tags = strings.Split(tags, ",")
+		}
+		if added, err := strconv.ParseInt(a.AttrOr("add_date", ""), 10, 64); err == nil {
+			favorite.CreatedAt = time.Unix(added, 0)
+		}
+
+		favorites = append(favorites, favorite)
+	})
+
+	return favorites
+}
+
+type categoryGroup struct {
+	category  string
+	favorites []Favorite
+}
+
+// groupByCategory groups favorites by category, keeping the order in which
+// each category first appears
+func groupByCategory(favorites []Favorite) []categoryGroup {
+	var groups []categoryGroup
+	index := make(map[string]int)
+
+	for _, f := range favorites {
+		category := f.Category
+		if category == "" {
+			category = uncategorized
+		}
+		i, ok := index[category]
+		if !ok {
+			i = len(groups)
+			index[category] = i
+			groups = append(groups, categoryGroup{category: category})
+		}
+		groups[i].favorites = append(groups[i].favorites, f)
+	}
+
+	return groups
+}
+
+// groupByCollection groups favorites by collection, in the order each
+// collection first appears. Favorites in several collections are listed in
+// each of them.
+func groupByCollection(favorites []Favorite) []categoryGroup {
+	var groups []categoryGroup
+	index := make(map[string]int)
+
+	for _, f := range favorites {
+		collections := f.Collections
+		if len(collections) == 0 {
+			collections = []string{uncategorized}
+		}
+		for _, collection := range collections {
+			i, ok := index[strings.ToLower(collection)]
+			if !ok {
+				i = len(groups)
+				index[strings.ToLower(collection)] = i
+				groups = append(groups, categoryGroup{category: collection})
+			}
+			groups[i].favorites = append(groups[i].favorites, f)
+		}
+	}
+
+	return groups
+}
+
+// headingAnchor returns the fragment GitHub links a markdown heading to
+func headingAnchor(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case r == ' ' || r == '-':
+			b.WriteRune('-')
+		case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// favoriteCategory maps the placeholder category written on export back to
+// an empty one
+func favoriteCategory(category string) string {
+	if category == uncategorized {
+		return ""
+	}
+	return category
+}
+
+// favoriteName falls back to the link for favorites without a name
+func favoriteName(f Favorite) string {
+	if f.Name != "" {
+		return f.Name
+	}
+	return f.Link
+}
+
+// escapeLinkText keeps brackets in a name from ending the markdown link
+func escapeLinkText(text string) string {
+	text = strings.ReplaceAll(text, "[", "\\[")
+	return strings.ReplaceAll(text, "]", "\\]")
+}
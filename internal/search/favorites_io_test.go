@@ -0,0 +1,138 @@
+package search
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFavoritesRoundTrip(t *testing.T) {
+	favorites := []Favorite{
+		{Link: "https://koala.com/", Name: "Koala", Description: "Sleeps a lot", Category: "Animals"},
+		{Link: "https://kangaroo.com/", Name: "Kanga & Roo", Category: "Animals"},
+		{Link: "https://wiki.js.org/", Name: "Wiki.js", Description: "A modern wiki"},
+	}
+
+	for _, format := range []FavoritesFormat{FavoritesJSON, FavoritesMarkdown, FavoritesHTML} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, ExportFavorites(&buf, favorites, format))
+
+			imported, err := ImportFavorites(&buf, format)
+			require.NoError(t, err)
+			assert.Equal(t, favorites, imported)
+		})
+	}
+}
+
+func TestBookmarksKeepTagsAndDates(t *testing.T) {
+	created := time.Unix(1700000000, 0)
+	favorites := []Favorite{
+		{Link: "https://koala.com/", Name: "Koala", Tags: []string{"marsupial", "sleepy"}, CreatedAt: created},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, ExportFavorites(&buf, favorites, FavoritesHTML))
+
+	imported, err := ImportFavorites(&buf, FavoritesHTML)
+	require.NoError(t, err)
+	require.Len(t, imported, 1)
+	assert.Equal(t, []string{"marsupial", "sleepy"}, imported[0].Tags)
+	assert.True(t, created.Equal(imported[0].CreatedAt))
+}
+
+func TestImportBrowserBookmarks(t *testing.T) {
+	// As exported by Firefox, with unclosed tags and nested folders
+	bookmarks := `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<TITLE>Bookmarks</TITLE>
+<H1>Bookmarks Menu</H1>
+<DL><p>
+    <DT><A HREF="https://top.example/" ADD_DATE="1700000000">Top level</A>
+    <DT><H3 ADD_DATE="1700000000">Tools</H3>
+    <DL><p>
+        <DT><A HREF="https://tool.example/">Tool</A>
+        <DD>Does things
+        <DT><H3>Nested</H3>
+        <DL><p>
+            <DT><A HREF="https://nested.example/">Nested tool</A>
+        </DL><p>
+        <DT><A HREF="place:sort=8">Recent tags</A>
+    </DL><p>
+</DL>`
+
+	favorites, err := ImportFavorites(strings.NewReader(bookmarks), FavoritesHTML)
+	require.NoError(t, err)
+	assert.Equal(t, []Favorite{
+		{Link: "https://top.example/", Name: "Top level", CreatedAt: time.Unix(1700000000, 0)},
+		{Link: "https://tool.example/", Name: "Tool", Description: "Does things", Category: "Tools"},
+		{Link: "https://nested.example/", Name: "Nested tool", Category: "Nested"},
+	}, favorites)
+}
+
+func TestMergeFavorites(t *testing.T) {
+	existing := []Favorite{{Link: "https://koala.com/", Name: "Koala"}}
+	imported := []Favorite{
+		{Link: "https://koala.com/", Name: "Renamed"},
+		{Link: "https://kangaroo.com/", Name: "Kangaroo"},
+		{Link: "https://kangaroo.com/", Name: "Duplicate"},
+		{Link: "http://www.koala.com?ref=bookmarks", Name: "Variant"},
+	}
+
+	merged, added := MergeFavorites(existing, imported)
+	assert.Equal(t, 1, added)
+	assert.Equal(t, []Favorite{
+		{Link: "https://koala.com/", Name: "Koala"},
+		{Link: "https://kangaroo.com/", Name: "Kangaroo"},
+	}, merged)
+}
+
+func TestExportAwesomeList(t *testing.T) {
+	favorites := []Favorite{
+		{Link: "https://js.wiki/", Name: "Wiki.js", Description: "A modern wiki", Collections: []string{"Docs Toolbox", "Self-hosted"}},
+		{Link: "https://koala.com/", Name: "Koala"},
+		{Link: "https://bookstack.app/", Name: "BookStack", Description: "Simple wiki.", Collections: []string{"Self-hosted"}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, ExportAwesomeList(&buf, favorites, AwesomeList{Title: "Awesome Team", Description: "What we use"}))
+	assert.Equal(t, `# Awesome Team
+
+> What we use
+
+## Contents
+
+- [Docs Toolbox](#docs-toolbox)
+- [Self-hosted](#self-hosted)
+- [Uncategorized](#uncategorized)
+
+## Docs Toolbox
+
+- [Wiki.js](https://js.wiki/) - A modern wiki.
+
+## Self-hosted
+
+- [Wiki.js](https://js.wiki/) - A modern wiki.
+- [BookStack](https://bookstack.app/) - Simple wiki.
+
+## Uncategorized
+
+- [Koala](https://koala.com/)
+`, buf.String())
+
+	// Importing it back skips the table of contents
+	imported, err := ImportFavorites(&buf, FavoritesAwesome)
+	require.NoError(t, err)
+	assert.Equal(t, []Favorite{
+		{Link: "https://js.wiki/", Name: "Wiki.js", Description: "A modern wiki", Category: "Docs Toolbox"},
+		{Link: "https://js.wiki/", Name: "Wiki.js", Description: "A modern wiki", Category: "Self-hosted"},
+		{Link: "https://bookstack.app/", Name: "BookStack", Description: "Simple wiki", Category: "Self-hosted"},
+		{Link: "https://koala.com/", Name: "Koala"},
+	}, imported)
+
+	assert.Error(t, ExportAwesomeList(&buf, favorites, AwesomeList{GroupBy: "colour"}))
+}
@@ -0,0 +1,401 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"freectl/internal/common"
+)
+
+const (
+	// maxHistoryEntries is how many past queries are kept
+	maxHistoryEntries = 500
+
+	// historyRepeatWindow is how soon a refined query replaces the previous
+	// one, so typing "wik" then "wiki" is recorded once
+	historyRepeatWindow = time.Minute
+
+	// savedResultsPerPage is how many results a saved search asks for at
+	// first. Searches matching more are run again for all of them.
+	savedResultsPerPage = 1000
+
+	// maxSavedAlerts is how many newly found results a saved search keeps
+	// for its feed
+	maxSavedAlerts = 100
+)
+
+// historyMu serialises changes to the history and saved searches files,
+// which the web server may write from several requests at once
+var historyMu sync.Mutex
+
+// HistoryEntry is a query that was searched
+type HistoryEntry struct {
+	Query      string        `json:"query"`
+	Filters    SearchFilters `json:"filters,omitzero"`
+	Results    int           `json:"results"`
+	SearchedAt time.Time     `json:"searched_at"`
+}
+
+// SavedSearch is a named query that can be re-run, remembering which results
+// it has already found
+type SavedSearch struct {
+	Name      string               `json:"name"`
+	Query     string               `json:"query"`
+	Filters   SearchFilters        `json:"filters,omitzero"`
+	CreatedAt time.Time            `json:"created_at"`
+	LastRunAt time.Time            `json:"last_run_at,omitzero"`
+	Seen      map[string]time.Time `json:"seen,omitempty"`   // result URL to when it was first found
+	Alerts    []SavedAlert         `json:"alerts,omitempty"` // results found after the first run, newest first
+}
+
+// SavedAlert is a result that started matching a saved search after its
+// first run, such as a resource a list added since
+type SavedAlert struct {
+	URL         string    `json:"url"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Category    string    `json:"category"`
+	Source      string    `json:"source"`
+	FoundAt     time.Time `json:"found_at"`
+}
+
+// SavedSearchRun is the outcome of running a saved search
+type SavedSearchRun struct {
+	Search   SavedSearch
+	Response *Response
+	New      []Result // results not found by any earlier run
+	FirstRun bool
+}
+
+func getHistoryPath() (string, error) {
+	return configPath("history.json")
+}
+
+func getSavedSearchesPath() (string, error) {
+	return configPath("saved_searches.json")
+}
+
+// loadJSON reads a config file, leaving v untouched when it doesn't exist
+func loadJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// saveJSON replaces a config file
+func saveJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadHistory returns past queries, most recent first
+func LoadHistory() ([]HistoryEntry, error) {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	history, err := loadHistory()
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(history)
+	return history, nil
+}
+
+// loadHistory returns past queries, oldest first as they are stored
+func loadHistory() ([]HistoryEntry, error) {
+	path, err := getHistoryPath()
+	if err != nil {
+		return nil, err
+	}
+
+	history := []HistoryEntry{}
+	if err := loadJSON(path, &history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// RecordSearch adds a query to the history. A query that refines or repeats
+// the previous one shortly after replaces it instead.
+func RecordSearch(query string, filters SearchFilters, results int) error {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	history, err := loadHistory()
+	if err != nil {
+		return err
+	}
+
+	entry := HistoryEntry{Query: query, Filters: filters, Results: results, SearchedAt: time.Now()}
+	if n := len(history); n > 0 && refinesQuery(history[n-1], entry) {
+		history[n-1] = entry
+	} else {
+		history = append(history, entry)
+	}
+
+	if len(history) > maxHistoryEntries {
+		history = history[len(history)-maxHistoryEntries:]
+	}
+
+	path, err := getHistoryPath()
+	if err != nil {
+		return err
+	}
+	return saveJSON(path, history)
+}
+
+// refinesQuery reports whether next was typed on top of previous, such as
+// while searching as you type
+func refinesQuery(previous, next HistoryEntry) bool {
+	if next.SearchedAt.Sub(previous.SearchedAt) > historyRepeatWindow {
+		return false
+	}
+	a, b := strings.ToLower(previous.Query), strings.ToLower(next.Query)
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
+
+// ClearHistory forgets every past query
+func ClearHistory() error {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	path, err := getHistoryPath()
+	if err != nil {
+		return err
+	}
+	return saveJSON(path, []HistoryEntry{})
+}
+
+// LoadSavedSearches returns the saved searches, sorted by name
+func LoadSavedSearches() ([]SavedSearch, error) {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+	return loadSavedSearches()
+}
+
+func loadSavedSearches() ([]SavedSearch, error) {
+	path, err := getSavedSearchesPath()
+	if err != nil {
+		return nil, err
+	}
+
+	saved := []SavedSearch{}
+	if err := loadJSON(path, &saved); err != nil {
+		return nil, err
+	}
+	sort.Slice(saved, func(i, j int) bool { return saved[i].Name < saved[j].Name })
+	return saved, nil
+}
+
+func storeSavedSearches(saved []SavedSearch) error {
+	path, err := getSavedSearchesPath()
+	if err != nil {
+		return err
+	}
+	return saveJSON(path, saved)
+}
+
+// GetSavedSearch returns the saved search with the given name
+func GetSavedSearch(name string) (SavedSearch, error) {
+	saved, err := LoadSavedSearches()
+	if err != nil {
+		return SavedSearch{}, err
+	}
+
+	for _, s := range saved {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+	return SavedSearch{}, fmt.Errorf("saved search '%s' not found", name)
+}
+
+// SaveSearch saves a query under a name. Saving a different query under an
+// existing name replaces it, and its results are all new again.
+func SaveSearch(name, query string, filters SearchFilters) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("saved search name is required")
+	}
+	if strings.ContainsAny(name, "/\\") {
+		return fmt.Errorf("saved search name cannot contain slashes")
+	}
+	if strings.TrimSpace(query) == "" {
+		return fmt.Errorf("saved search query is required")
+	}
+
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	saved, err := loadSavedSearches()
+	if err != nil {
+		return err
+	}
+
+	search := SavedSearch{Name: name, Query: query, Filters: filters, CreatedAt: time.Now()}
+	for i, s := range saved {
+		if s.Name != name {
+			continue
+		}
+		if s.Query == query && reflect.DeepEqual(s.Filters, filters) {
+			search.CreatedAt, search.LastRunAt, search.Seen, search.Alerts = s.CreatedAt, s.LastRunAt, s.Seen, s.Alerts
+		}
+		saved[i] = search
+		return storeSavedSearches(saved)
+	}
+
+	return storeSavedSearches(append(saved, search))
+}
+
+// DeleteSavedSearch forgets a saved search
+func DeleteSavedSearch(name string) error {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	saved, err := loadSavedSearches()
+	if err != nil {
+		return err
+	}
+
+	remaining := slices.DeleteFunc(slices.Clone(saved), func(s SavedSearch) bool { return s.Name == name })
+	if len(remaining) == len(saved) {
+		return fmt.Errorf("saved search '%s' not found", name)
+	}
+	return storeSavedSearches(remaining)
+}
+
+// RunSavedSearch runs a saved search and reports which results no earlier
+// run had found. Every result is new on the first run.
+func RunSavedSearch(ctx context.Context, searcher Searcher, name string) (*SavedSearchRun, error) {
+	saved, err := GetSavedSearch(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every match is compared, so a result moving up the ranking isn't new
+	req := Request{Query: saved.Query, Filters: saved.Filters, PerPage: savedResultsPerPage}
+	resp, err := searcher.Search(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.TotalResults > len(resp.Results) {
+		req.PerPage = resp.TotalResults
+		if resp, err = searcher.Search(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	// Reload in case another run finished while this one was searching
+	all, err := loadSavedSearches()
+	if err != nil {
+		return nil, err
+	}
+	index := slices.IndexFunc(all, func(s SavedSearch) bool { return s.Name == name })
+	if index == -1 {
+		return nil, fmt.Errorf("saved search '%s' not found", name)
+	}
+	saved = all[index]
+
+	run := &SavedSearchRun{Response: resp, New: []Result{}, FirstRun: saved.LastRunAt.IsZero()}
+	now := time.Now()
+	// Results that stopped matching are forgotten, and are new once they
+	// match again
+	seen := make(map[string]time.Time, len(resp.Results))
+	for _, result := range resp.Results {
+		key := common.CanonicalURL(result.URL)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		if found, ok := saved.Seen[key]; ok {
+			seen[key] = found
+			continue
+		}
+		seen[key] = now
+		run.New = append(run.New, result)
+	}
+	saved.Seen = seen
+	saved.LastRunAt = now
+
+	// The first run only sets the baseline, later ones raise alerts
+	if !run.FirstRun && len(run.New) > 0 {
+		alerts := make([]SavedAlert, 0, len(run.New)+len(saved.Alerts))
+		for _, result := range run.New {
+			alerts = append(alerts, SavedAlert{
+				URL:         result.URL,
+				Name:        result.Name,
+				Description: result.Description,
+				Category:    result.Category,
+				Source:      result.Source,
+				FoundAt:     now,
+			})
+		}
+		saved.Alerts = append(alerts, saved.Alerts...)
+		if len(saved.Alerts) > maxSavedAlerts {
+			saved.Alerts = saved.Alerts[:maxSavedAlerts]
+		}
+	}
+
+	all[index] = saved
+	if err := storeSavedSearches(all); err != nil {
+		return nil, err
+	}
+
+	run.Search = saved
+	return run, nil
+}
+
+// RefreshSavedSearches runs every saved search, such as after sources were
+// updated, and returns how many new results they found in total. A failing
+// search doesn't stop the others.
+func RefreshSavedSearches(ctx context.Context, searcher Searcher) (int, error) {
+	saved, err := LoadSavedSearches()
+	if err != nil {
+		return 0, err
+	}
+
+	found := 0
+	var errs []error
+	for _, s := range saved {
+		run, err := RunSavedSearch(ctx, searcher, s.Name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("saved search '%s': %w", s.Name, err))
+			continue
+		}
+		if !run.FirstRun {
+			found += len(run.New)
+		}
+	}
+
+	return found, errors.Join(errs...)
+}
@@ -0,0 +1,144 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubSearcher returns the first page of a fixed set of results for any
+// request
+type stubSearcher struct {
+	results []Result
+}
+
+func (s *stubSearcher) Name() string { return "stub" }
+
+func (s *stubSearcher) Search(ctx context.Context, req Request) (*Response, error) {
+	page := s.results
+	if req.PerPage > 0 && len(page) > req.PerPage {
+		page = page[:req.PerPage]
+	}
+	return &Response{Results: page, TotalResults: len(s.results)}, nil
+}
+
+func TestRecordSearch(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	require.NoError(t, RecordSearch("wik", SearchFilters{}, 3))
+	require.NoError(t, RecordSearch("wiki", SearchFilters{}, 5))
+	require.NoError(t, RecordSearch("torrent", SearchFilters{Sources: []string{"piracy"}}, 7))
+	require.NoError(t, RecordSearch("  ", SearchFilters{}, 0))
+
+	history, err := LoadHistory()
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "torrent", history[0].Query)
+	assert.Equal(t, []string{"piracy"}, history[0].Filters.Sources)
+	assert.Equal(t, 7, history[0].Results)
+	assert.Equal(t, "wiki", history[1].Query)
+	assert.Equal(t, 5, history[1].Results)
+
+	require.NoError(t, ClearHistory())
+	history, err = LoadHistory()
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func TestRunSavedSearch(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	require.NoError(t, SaveSearch("wikis", "self-hosted wiki", SearchFilters{}))
+	assert.Error(t, SaveSearch("a/b", "wiki", SearchFilters{}))
+	assert.Error(t, SaveSearch("empty", " ", SearchFilters{}))
+
+	searcher := &stubSearcher{results: []Result{{URL: "https://js.wiki/"}, {URL: "https://bookstack.app/"}}}
+	run, err := RunSavedSearch(context.Background(), searcher, "wikis")
+	require.NoError(t, err)
+	assert.True(t, run.FirstRun)
+	assert.Len(t, run.New, 2)
+
+	searcher.results = append(searcher.results, Result{URL: "https://dokuwiki.org/"})
+	run, err = RunSavedSearch(context.Background(), searcher, "wikis")
+	require.NoError(t, err)
+	assert.False(t, run.FirstRun)
+	require.Len(t, run.New, 1)
+	assert.Equal(t, "https://dokuwiki.org/", run.New[0].URL)
+	assert.Len(t, run.Response.Results, 3)
+
+	// Only results found after the first run are alerts
+	require.Len(t, run.Search.Alerts, 1)
+	assert.Equal(t, "https://dokuwiki.org/", run.Search.Alerts[0].URL)
+	assert.False(t, run.Search.Alerts[0].FoundAt.IsZero())
+
+	// Re-saving the same query keeps what was already found
+	require.NoError(t, SaveSearch("wikis", "self-hosted wiki", SearchFilters{}))
+	run, err = RunSavedSearch(context.Background(), searcher, "wikis")
+	require.NoError(t, err)
+	assert.Empty(t, run.New)
+
+	// A different query starts over
+	require.NoError(t, SaveSearch("wikis", "wiki", SearchFilters{}))
+	run, err = RunSavedSearch(context.Background(), searcher, "wikis")
+	require.NoError(t, err)
+	assert.Len(t, run.New, 3)
+
+	require.NoError(t, DeleteSavedSearch("wikis"))
+	assert.Error(t, DeleteSavedSearch("wikis"))
+	_, err = RunSavedSearch(context.Background(), searcher, "wikis")
+	assert.Error(t, err)
+}
+
+func TestRunSavedSearchComparesEveryMatch(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	require.NoError(t, SaveSearch("links", "link", SearchFilters{}))
+
+	var results []Result
+	for i := range savedResultsPerPage + 1 {
+		results = append(results, Result{URL: fmt.Sprintf("https://example.com/%d", i)})
+	}
+	searcher := &stubSearcher{results: results}
+	_, err := RunSavedSearch(context.Background(), searcher, "links")
+	require.NoError(t, err)
+
+	// The last match moving to the top was already found
+	last := results[len(results)-1]
+	searcher.results = append([]Result{last}, results[:len(results)-1]...)
+	run, err := RunSavedSearch(context.Background(), searcher, "links")
+	require.NoError(t, err)
+	assert.Empty(t, run.New)
+	assert.Len(t, run.Search.Seen, savedResultsPerPage+1)
+
+	// Results that stop matching are forgotten, and new when they're back
+	searcher.results = results[1:]
+	run, err = RunSavedSearch(context.Background(), searcher, "links")
+	require.NoError(t, err)
+	assert.Empty(t, run.New)
+	assert.Len(t, run.Search.Seen, savedResultsPerPage)
+
+	searcher.results = results
+	run, err = RunSavedSearch(context.Background(), searcher, "links")
+	require.NoError(t, err)
+	require.Len(t, run.New, 1)
+	assert.Equal(t, results[0].URL, run.New[0].URL)
+}
+
+func TestRefreshSavedSearches(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	require.NoError(t, SaveSearch("wikis", "wiki", SearchFilters{}))
+	require.NoError(t, SaveSearch("torrents", "torrent", SearchFilters{}))
+
+	searcher := &stubSearcher{results: []Result{{URL: "https://js.wiki/"}}}
+	found, err := RefreshSavedSearches(context.Background(), searcher)
+	require.NoError(t, err)
+	assert.Equal(t, 0, found, "first runs only set the baseline")
+
+	searcher.results = append(searcher.results, Result{URL: "https://bookstack.app/"})
+	found, err = RefreshSavedSearches(context.Background(), searcher)
+	require.NoError(t, err)
+	assert.Equal(t, 2, found)
+}
@@ -2,9 +2,12 @@ package search
 
 import (
 	"fmt"
-	"sort"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 
+	"freectl/internal/common"
 	"freectl/internal/preprocessing"
 	"freectl/internal/settings"
 
@@ -12,93 +15,66 @@ import (
 	"github.com/sahilm/fuzzy"
 )
 
-// SearchPreprocessed performs search on preprocessed JSON data instead of parsing markdown in real-time
-func SearchPreprocessed(query string, sourceName string, s settings.Settings, limit int) ([]Result, error) {
-	// Get storage for processed data
-	storage := preprocessing.NewFileStorage(s.CacheDir + "/processed")
-
-	// Get list of processed sources
-	processedSources, err := storage.List()
-	if err != nil {
-		return nil, fmt.Errorf("failed to list processed sources: %w", err)
-	}
-
-	if len(processedSources) == 0 {
-		return nil, fmt.Errorf("no processed sources found. Run 'freectl process' first")
-	}
-
-	log.Info("Found processed sources", "count", len(processedSources))
-
-	// Filter by source name if specified
-	if sourceName != "" {
-		var found bool
-		for _, name := range processedSources {
-			if name == sourceName || strings.EqualFold(name, sourceName) {
-				processedSources = []string{name}
-				found = true
-				break
-			}
-		}
-		if !found {
-			return nil, fmt.Errorf("processed source '%s' not found", sourceName)
-		}
-	}
-
-	// Load all requested sources
-	var allItems []SearchableItem
-	for _, sourceName := range processedSources {
-		processed, err := storage.Load(sourceName)
-		if err != nil {
-			log.Error("Failed to load processed source", "name", sourceName, "error", err)
-			continue
-		}
-
-		// Convert preprocessed items to searchable items
-		for _, item := range processed.Items {
-			searchableItem := SearchableItem{
-				URL:         item.URL,
-				Name:        item.Name,
-				Description: item.Description,
-				Category:    item.Category,
-				Source:      processed.Source.Name,
-				Tags:        item.Tags,
-				RawText:     item.RawText,
-			}
-			allItems = append(allItems, searchableItem)
-		}
-	}
-
-	if len(allItems) == 0 {
-		return nil, fmt.Errorf("no items found in processed sources")
-	}
-
-	log.Info("Loaded items for search", "total", len(allItems))
-
-	// Perform fuzzy search
-	results := performFuzzySearch(query, allItems, s, limit)
-
-	log.Info("Search completed", "query", query, "results", len(results))
-	return results, nil
-}
-
 // SearchableItem represents an item that can be searched
 type SearchableItem struct {
-	URL         string   `json:"url"`
-	Name        string   `json:"name"`
-	Description string   `json:"description"`
-	Category    string   `json:"category"`
-	Source      string   `json:"source"`
-	Tags        []string `json:"tags"`
-	RawText     string   `json:"raw_text"`
+	URL          string   `json:"url"`
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	Category     string   `json:"category"`
+	Source       string   `json:"source"`
+	SourceType   string   `json:"source_type"`
+	Tags         []string `json:"tags"`
+	RawText      string   `json:"raw_text"`
+	CategoryPath []string `json:"category_path,omitempty"`
+	Notes        string   `json:"notes,omitempty"`
+	Collections  []string `json:"collections,omitempty"`
+	FilePath     string   `json:"file_path,omitempty"`
+	LineNumber   int      `json:"line_number,omitempty"`
+	SourceURL    string   `json:"source_url,omitempty"`
+	LinkStatus   int      `json:"link_status,omitempty"`
+	Dead         bool     `json:"dead,omitempty"`
 }
 
-// performFuzzySearch executes fuzzy search on the items
-func performFuzzySearch(query string, items []SearchableItem, s settings.Settings, limit int) []Result {
+// toSearchableItems converts the items of a processed source for searching
+func toSearchableItems(processed *preprocessing.ProcessedSource) []SearchableItem {
+	items := make([]SearchableItem, 0, len(processed.Items))
+	source := processed.Source
+	for _, item := range processed.Items {
+		meta := item.Metadata
+		items = append(items, SearchableItem{
+			URL:          item.URL,
+			Name:         item.Name,
+			Description:  item.Description,
+			Category:     item.Category,
+			Source:       source.Name,
+			SourceType:   source.Type,
+			Tags:         item.Tags,
+			RawText:      item.RawText,
+			CategoryPath: meta.HeadingHierarchy,
+			FilePath:     meta.FilePath,
+			LineNumber:   meta.LineNumber,
+			SourceURL:    viewSourceURL(source.Type, source.URL, source.Revision, meta.FilePath, meta.LineNumber),
+			LinkStatus:   item.Health.StatusCode,
+			Dead:         item.Health.Dead(),
+		})
+	}
+	return items
+}
+
+// performFuzzySearch executes fuzzy search on the items and returns every
+// match, ranked by score
+func performFuzzySearch(query string, items []SearchableItem, s settings.Settings) []Result {
 	// Create searchable strings for each item
 	searchStrings := make([]string, len(items))
 	for i, item := range items {
-		// Combine name, description, and tags for searching
+		// Combine name, description, tags and any notes and collections for searching
 		searchText := fmt.Sprintf("%s %s %s", item.Name, item.Description, strings.Join(item.Tags, " "))
+		if item.Notes != "" {
+			searchText += " " + item.Notes
+		}
+		if len(item.Collections) > 0 {
+			searchText += " " + strings.Join(item.Collections, " ")
+		}
 		searchStrings[i] = searchText
 	}
 
@@ -114,142 +90,110 @@ func performFuzzySearch(query string, items []SearchableItem, s settings.Setting
 
 		item := items[match.Index]
 		result := Result{
-			URL:         item.URL,
-			Name:        item.Name,
-			Description: item.Description,
-			Category:    item.Category,
-			Source:      item.Source,
-			Tags:        item.Tags,
-			Score:       match.Score,
+			URL:          item.URL,
+			Name:         item.Name,
+			Description:  item.Description,
+			Category:     item.Category,
+			Source:       item.Source,
+			SourceType:   item.SourceType,
+			Tags:         item.Tags,
+			Collections:  item.Collections,
+			Score:        match.Score,
+			Line:         item.RawText,
+			CategoryPath: item.CategoryPath,
+			FilePath:     item.FilePath,
+			LineNumber:   item.LineNumber,
+			SourceURL:    item.SourceURL,
+			LinkStatus:   item.LinkStatus,
+			Dead:         item.Dead,
 		}
 		results = append(results, result)
 	}
 
-	// Sort results by score (highest first)
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].Score > results[j].Score
-	})
-
-	// Normalize scores to 0-100 scale
-	if len(results) > 0 {
-		maxScore := results[0].Score
-		for i := range results {
-			results[i].Score = int((float64(results[i].Score) / float64(maxScore)) * 100)
-		}
-	}
-
-	// Apply limit from command line if specified, otherwise use settings
-	if limit > 0 && len(results) > limit {
-		results = results[:limit]
-	} else if limit == 0 && s.ResultsPerPage > 0 && len(results) > s.ResultsPerPage {
-		results = results[:s.ResultsPerPage]
-	}
+	// Sort results by score (highest first) and normalize to a 0-100 scale
+	rankResults(results)
 
 	return results
 }
 
-// SearchPreprocessedAdvanced performs advanced search with filters
-func SearchPreprocessedAdvanced(query string, filters SearchFilters, s settings.Settings) ([]Result, error) {
-	// Get storage for processed data
-	storage := preprocessing.NewFileStorage(s.CacheDir + "/processed")
+// SearchFilters represents search filtering options
+type SearchFilters struct {
+	Sources     []string `json:"sources,omitempty"`
+	Categories  []string `json:"categories,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Collections []string `json:"collections,omitempty"`
+	Domains     []string `json:"domains,omitempty"`
+	SourceTypes []string `json:"source_types,omitempty"`
+	MinScore    int      `json:"min_score,omitempty"`
+}
 
-	// Get list of processed sources
-	processedSources, err := storage.List()
-	if err != nil {
-		return nil, fmt.Errorf("failed to list processed sources: %w", err)
-	}
+// linkHealthCache keeps the link health loaded for live searches, which
+// would otherwise read every processed source on each keystroke
+var linkHealthCache struct {
+	sync.Mutex
+	key    string
+	health map[string]preprocessing.LinkHealth
+}
+
+// loadLinkHealth returns the checked links of every processed source, keyed
+// by canonical URL. It's loaded again only once the processed files change.
+func loadLinkHealth(cacheDir string) map[string]preprocessing.LinkHealth {
+	dir := filepath.Join(cacheDir, "processed")
+	key := processedFilesKey(dir)
 
-	if len(processedSources) == 0 {
-		return nil, fmt.Errorf("no processed sources found. Run 'freectl process' first")
+	linkHealthCache.Lock()
+	defer linkHealthCache.Unlock()
+	if linkHealthCache.health != nil && linkHealthCache.key == key {
+		return linkHealthCache.health
 	}
 
-	// Filter sources based on filters
-	if len(filters.Sources) > 0 {
-		var filteredSources []string
-		for _, sourceName := range processedSources {
-			for _, filterSource := range filters.Sources {
-				if strings.EqualFold(sourceName, filterSource) {
-					filteredSources = append(filteredSources, sourceName)
-					break
-				}
-			}
-		}
-		processedSources = filteredSources
+	storage := preprocessing.NewFileStorage(dir)
+	names, err := storage.List()
+	if err != nil {
+		log.Debug("Failed to list processed sources", "error", err)
+		return nil
 	}
 
-	// Load and filter items
-	var allItems []SearchableItem
-	for _, sourceName := range processedSources {
-		processed, err := storage.Load(sourceName)
+	health := make(map[string]preprocessing.LinkHealth)
+	for _, name := range names {
+		processed, err := storage.Load(name)
 		if err != nil {
-			log.Error("Failed to load processed source", "name", sourceName, "error", err)
+			log.Debug("Failed to load processed source", "name", name, "error", err)
 			continue
 		}
-
-		// Convert and filter preprocessed items
 		for _, item := range processed.Items {
-			// Apply category filter
-			if len(filters.Categories) > 0 {
-				matchesCategory := false
-				for _, filterCategory := range filters.Categories {
-					if strings.Contains(strings.ToLower(item.Category), strings.ToLower(filterCategory)) {
-						matchesCategory = true
-						break
-					}
-				}
-				if !matchesCategory {
-					continue
-				}
-			}
-
-			// Apply tag filter
-			if len(filters.Tags) > 0 {
-				matchesTag := false
-				for _, filterTag := range filters.Tags {
-					for _, itemTag := range item.Tags {
-						if strings.EqualFold(itemTag, filterTag) {
-							matchesTag = true
-							break
-						}
-					}
-					if matchesTag {
-						break
-					}
-				}
-				if !matchesTag {
-					continue
-				}
-			}
-
-			searchableItem := SearchableItem{
-				URL:         item.URL,
-				Name:        item.Name,
-				Description: item.Description,
-				Category:    item.Category,
-				Source:      processed.Source.Name,
-				Tags:        item.Tags,
-				RawText:     item.RawText,
+			if item.Health.Checked() {
+				health[common.CanonicalURL(item.URL)] = item.Health
 			}
-			allItems = append(allItems, searchableItem)
 		}
 	}
 
-	if len(allItems) == 0 {
-		return []Result{}, nil
-	}
-
-	// Perform fuzzy search (no limit for advanced search)
-	results := performFuzzySearch(query, allItems, s, 0)
-
-	return results, nil
+	linkHealthCache.key = key
+	linkHealthCache.health = health
+	return health
 }
 
-// SearchFilters represents search filtering options
-type SearchFilters struct {
-	Sources    []string `json:"sources,omitempty"`
-	Categories []string `json:"categories,omitempty"`
-	Tags       []string `json:"tags,omitempty"`
-	MinScore   int      `json:"min_score,omitempty"`
+// processedFilesKey identifies the processed files in dir as they are now,
+// by their names, sizes and modification times
+func processedFilesKey(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return dir
+	}
+
+	var key strings.Builder
+	key.WriteString(dir)
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&key, "|%s:%d:%d", entry.Name(), info.Size(), info.ModTime().UnixNano())
+	}
+	return key.String()
 }
 
 // GetProcessedSources returns a list of all processed source names
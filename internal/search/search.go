@@ -1,6 +1,8 @@
 package search
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"net/url"
 	"os"
@@ -8,8 +10,10 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"freectl/internal/common"
+	"freectl/internal/preprocessing/extractors"
 	"freectl/internal/settings"
 	"freectl/internal/sources"
 
@@ -18,6 +22,7 @@ import (
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/text"
 )
@@ -26,11 +31,43 @@ type Result struct {
 	URL         string   `json:"url"`
 	Name        string   `json:"name"`
 	Description string   `json:"description"`
-	Line        string   `json:"-"`
+	Line        string   `json:"-"` // the raw source line the link was found on
 	Score       int      `json:"-"`
 	Category    string   `json:"title"`
 	Source      string   `json:"source"`
-	Tags        []string `json:"tags"` // not used yet
+	SourceType  string   `json:"source_type"`
+	Tags        []string `json:"tags"`
+
+	// Collections holds the favorites collections the result is in
+	Collections []string `json:"collections,omitempty"`
+
+	// CategoryPath holds the headings above the result, outermost first
+	CategoryPath []string `json:"category_path,omitempty"`
+
+	// FilePath and LineNumber locate the link within its source, and
+	// SourceURL shows that line upstream for git sources on known hosts
+	FilePath   string `json:"file_path,omitempty"`
+	LineNumber int    `json:"line_number,omitempty"`
+	SourceURL  string `json:"source_url,omitempty"`
+
+	// Appearances lists every source, category and description the URL was
+	// found under, and AppearsIn counts the distinct sources among them.
+	// Both are only set once results from several sources have been merged.
+	Appearances []Appearance `json:"appearances,omitempty"`
+	AppearsIn   int          `json:"appears_in,omitempty"`
+
+	// LinkStatus is the HTTP status the link answered with when it was last
+	// checked, and Dead is set when it no longer works
+	LinkStatus int  `json:"link_status,omitempty"`
+	Dead       bool `json:"dead,omitempty"`
+}
+
+// Appearance records one place a merged result was found
+type Appearance struct {
+	Source      string `json:"source"`
+	Category    string `json:"category"`
+	Description string `json:"description"`
+	SourceURL   string `json:"source_url,omitempty"`
 }
 
 // isLinkHeavyReadme checks if a README.md file contains a significant number of links
@@ -201,8 +238,36 @@ func isLocalURL(urlStr string) bool {
 		strings.HasPrefix(host, "172.")
 }
 
-// Search performs a fuzzy search across all markdown files using goldmark for parsing
-func Search(query string, sourceName string, s settings.Settings) ([]Result, error) {
+// withSearchTimeout bounds ctx by the configured per-query timeout, if any
+func withSearchTimeout(ctx context.Context, s settings.Settings) (context.Context, context.CancelFunc) {
+	if s.SearchTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Duration(s.SearchTimeout)*time.Millisecond)
+}
+
+// SourceBatch holds the results found in a single source during a streamed search
+type SourceBatch struct {
+	Source  string   `json:"source"`
+	Results []Result `json:"results"`
+}
+
+// Search performs a fuzzy search across all markdown files using goldmark for parsing.
+// The search stops early and returns ctx.Err() when ctx is cancelled or the
+// configured search timeout elapses.
+func Search(ctx context.Context, query string, sourceName string, s settings.Settings) ([]Result, error) {
+	return SearchStream(ctx, query, sourceName, s, nil)
+}
+
+// SearchStream behaves like Search but additionally sends each source's
+// results on batches as soon as that source has been searched, so callers can
+// show early results while slower sources are still being walked. Scores in a
+// batch are only normalised within that source; the returned slice is the
+// final ranking across all sources. batches may be nil and is never closed.
+func SearchStream(ctx context.Context, query string, sourceName string, s settings.Settings, batches chan<- SourceBatch) ([]Result, error) {
+	ctx, cancel := withSearchTimeout(ctx, s)
+	defer cancel()
+
 	// Get list of sources from settings
 	sourceList := s.Sources
 	if sourceList == nil {
@@ -262,188 +327,346 @@ func Search(query string, sourceName string, s settings.Settings) ([]Result, err
 	semaphore := make(chan struct{}, numWorkers)
 
 	// Process each source
+sourceLoop:
 	for _, source := range enabledSources {
+		// Acquire semaphore, giving up if the search is abandoned
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			break sourceLoop
+		}
 		wg.Add(1)
-		semaphore <- struct{}{} // Acquire semaphore
 
 		go func(src sources.Source) {
 			defer wg.Done()
 			defer func() { <-semaphore }() // Release semaphore
 
-			sourcePath := src.Path
-			log.Info("Searching in source", "name", src.Name, "path", sourcePath)
+			sourceResults := searchSource(ctx, md, src, query, s)
+			resultChan <- sourceResults
+		}(source)
+	}
+
+	// Wait for all goroutines to complete
+	go func() {
+		wg.Wait()
+		close(resultChan)
+	}()
+
+	// Collect results from all sources, forwarding each batch as it arrives
+	var allResults []Result
+	for results := range resultChan {
+		allResults = append(allResults, results...)
+
+		if batches != nil && len(results) > 0 && ctx.Err() == nil {
+			batch := make([]Result, len(results))
+			copy(batch, results)
+			rankResults(batch)
+			select {
+			case batches <- SourceBatch{Source: batch[0].Source, Results: batch}:
+			case <-ctx.Done():
+			}
+		}
+	}
 
-			var sourceResults []Result
-			var sourceMu sync.Mutex
+	if err := ctx.Err(); err != nil {
+		log.Debug("Search abandoned", "query", query, "error", err)
+		return nil, err
+	}
 
-			// Walk through all markdown files in the source
-			err := filepath.Walk(sourcePath, func(path string, info os.FileInfo, err error) error {
-				if err != nil {
-					log.Error("Error accessing path", "path", path, "error", err)
-					return nil // Skip this file but continue walking
-				}
+	rankResults(allResults)
+	return allResults, nil
+}
 
-				// Skip directories and non-markdown files
-				if info.IsDir() || !strings.HasSuffix(path, ".md") {
-					return nil
+// rankResults sorts results by score and normalises the scores to a 0-100 scale
+func rankResults(results []Result) {
+	// Sort results by score
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	// Normalize scores
+	if len(results) > 0 {
+		maxScore := results[0].Score
+		for i := range results {
+			results[i].Score = int((float64(results[i].Score) / float64(maxScore)) * 100)
+		}
+	}
+}
+
+// linkOffset returns the byte offset in content that a link node starts at,
+// or -1 when it can't be located
+func linkOffset(content []byte, link *ast.Link) int {
+	offset := -1
+	for c := link.FirstChild(); c != nil && offset < 0; c = c.NextSibling() {
+		if t, ok := c.(*ast.Text); ok {
+			offset = t.Segment.Start
+		}
+	}
+
+	// Fall back to the first line of the enclosing block
+	if offset < 0 {
+		for n := link.Parent(); n != nil; n = n.Parent() {
+			if n.Type() == ast.TypeBlock && n.Lines().Len() > 0 {
+				offset = n.Lines().At(0).Start
+				break
+			}
+		}
+	}
+	if offset > len(content) {
+		return -1
+	}
+	return offset
+}
+
+// sourceLine returns the line of content that a link node starts on
+func sourceLine(content []byte, link *ast.Link) string {
+	offset := linkOffset(content, link)
+	if offset < 0 {
+		return ""
+	}
+
+	start := bytes.LastIndexByte(content[:offset], '\n') + 1
+	end := bytes.IndexByte(content[offset:], '\n')
+	if end < 0 {
+		end = len(content)
+	} else {
+		end += offset
+	}
+	return strings.TrimSpace(string(content[start:end]))
+}
+
+// lineNumber returns the 1-based line number a link node starts on, or 0
+// when it can't be located
+func lineNumber(content []byte, link *ast.Link) int {
+	offset := linkOffset(content, link)
+	if offset < 0 {
+		return 0
+	}
+	return bytes.Count(content[:offset], []byte("\n")) + 1
+}
+
+// tableRowContext describes a table row by its description column, or by all
+// of its cells when the table has none
+func tableRowContext(row ast.Node, roles []string, content []byte) string {
+	var cells []string
+	i := 0
+	for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+		text := strings.TrimSpace(getNodeText(cell, content))
+		if i < len(roles) && roles[i] == extractors.ColumnDescription {
+			return text
+		}
+		if text != "" {
+			cells = append(cells, text)
+		}
+		i++
+	}
+	return strings.Join(cells, " - ")
+}
+
+// viewSourceURL returns the upstream URL showing a line of a source file,
+// or "" when the source isn't a git repository on a known host
+func viewSourceURL(sourceType, repoURL, revision, filePath string, line int) string {
+	if sourceType != string(sources.SourceTypeGit) {
+		return ""
+	}
+	return sources.GitFileURL(repoURL, revision, filePath, line)
+}
+
+// searchSource walks every content file of a single source and returns the
+// raw, unsorted matches for query
+func searchSource(ctx context.Context, md goldmark.Markdown, src sources.Source, query string, s settings.Settings) []Result {
+	sourcePath := src.Path
+	log.Info("Searching in source", "name", src.Name, "path", sourcePath)
+
+	var sourceResults []Result
+	var sourceMu sync.Mutex
+
+	// Walk through all markdown files in the source
+	err := filepath.Walk(sourcePath, func(path string, info os.FileInfo, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		if err != nil {
+			log.Error("Error accessing path", "path", path, "error", err)
+			return nil // Skip this file but continue walking
+		}
+
+		// Skip directories and non-markdown files
+		if info.IsDir() || !strings.HasSuffix(path, ".md") {
+			return nil
+		}
+
+		// Skip non-content files
+		if !isContentFile(path) {
+			log.Debug("Skipping non-content file", "path", path)
+			return nil
+		}
+
+		log.Debug("Processing markdown file", "path", path)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			log.Error("Error reading file", "path", path, "error", err)
+			return nil
+		}
+
+		relPath, err := filepath.Rel(sourcePath, path)
+		if err != nil {
+			relPath = path
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		// Parse the markdown content
+		doc := md.Parser().Parse(text.NewReader(content))
+
+		// Track headings by level
+		headings := make(map[int]string)
+		var currentLevel int
+		var currentContext string
+		var contextNode ast.Node
+		var insideHeading bool
+		var tableRoles []string
+
+		// Walk through the AST
+		ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+			if !entering {
+				if n == contextNode {
+					currentContext = ""
+					contextNode = nil
+				}
+				if _, ok := n.(*ast.Heading); ok {
+					insideHeading = false
 				}
+				return ast.WalkContinue, nil
+			}
 
-				// Skip non-content files
-				if !isContentFile(path) {
-					log.Debug("Skipping non-content file", "path", path)
-					return nil
+			switch v := n.(type) {
+			case *ast.Heading:
+				headingText := getNodeText(v, content)
+				cleanHeading := common.CleanCategory(headingText)
+				currentLevel = v.Level
+				headings[currentLevel] = cleanHeading
+				currentContext = headingText
+				contextNode = v
+				insideHeading = true
+
+			case *ast.Paragraph, *ast.ListItem:
+				currentContext = getNodeText(v, content)
+				contextNode = v
+
+			case *east.TableHeader:
+				// Remember what the columns hold to describe the rows
+				tableRoles = tableRoles[:0]
+				for cell := v.FirstChild(); cell != nil; cell = cell.NextSibling() {
+					tableRoles = append(tableRoles, extractors.TableColumnRole(getNodeText(cell, content)))
 				}
+				return ast.WalkSkipChildren, nil
+
+			case *east.TableRow:
+				currentContext = tableRowContext(v, tableRoles, content)
+				contextNode = v
 
-				log.Debug("Processing markdown file", "path", path)
-				content, err := os.ReadFile(path)
-				if err != nil {
-					log.Error("Error reading file", "path", path, "error", err)
-					return nil
+			case *ast.Link:
+				// Get the link destination
+				destination := v.Destination
+				if len(destination) == 0 {
+					return ast.WalkContinue, nil
 				}
+				url := string(destination)
 
-				// Parse the markdown content
-				doc := md.Parser().Parse(text.NewReader(content))
-
-				// Track headings by level
-				headings := make(map[int]string)
-				var currentLevel int
-				var currentContext string
-				var contextNode ast.Node
-				var insideHeading bool
-
-				// Walk through the AST
-				ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
-					if !entering {
-						if n == contextNode {
-							currentContext = ""
-							contextNode = nil
-						}
-						if _, ok := n.(*ast.Heading); ok {
-							insideHeading = false
-						}
-						return ast.WalkContinue, nil
-					}
+				// Badges annotate the entry next to them rather than being
+				// results of their own
+				if _, badge := v.FirstChild().(*ast.Image); badge || extractors.IsBadgeURL(url) {
+					return ast.WalkSkipChildren, nil
+				}
 
-					switch v := n.(type) {
-					case *ast.Heading:
-						headingText := getNodeText(v, content)
-						cleanHeading := common.CleanCategory(headingText)
-						currentLevel = v.Level
-						headings[currentLevel] = cleanHeading
-						currentContext = headingText
-						contextNode = v
-						insideHeading = true
-
-					case *ast.Paragraph, *ast.ListItem:
-						currentContext = getNodeText(v, content)
-						contextNode = v
-
-					case *ast.Link:
-						// Get the link destination
-						destination := v.Destination
-						if len(destination) == 0 {
-							return ast.WalkContinue, nil
-						}
-						url := string(destination)
+				// Get link text
+				linkText := getNodeText(v, content)
+				if linkText == "" {
+					linkText = url
+				}
 
-						// Get link text
-						linkText := getNodeText(v, content)
-						if linkText == "" {
-							linkText = url
-						}
+				// Skip single-character link texts
+				if len(strings.TrimSpace(linkText)) <= 1 {
+					return ast.WalkContinue, nil
+				}
 
-						// Skip single-character link texts
-						if len(strings.TrimSpace(linkText)) <= 1 {
-							return ast.WalkContinue, nil
-						}
+				// Use the current context as description
+				var description string
+				if currentContext != "" {
+					description = common.CleanDescription(currentContext)
+				} else {
+					description = linkText
+				}
 
-						// Use the current context as description
-						var description string
-						if currentContext != "" {
-							description = common.CleanDescription(currentContext)
-						} else {
-							description = linkText
-						}
+				// Search in both description and link text
+				matches := fuzzy.Find(query, []string{description, linkText})
+				if len(matches) > 0 && matches[0].Score >= s.MinFuzzyScore {
+					if isLocalURL(url) {
+						return ast.WalkContinue, nil
+					}
 
-						// Search in both description and link text
-						matches := fuzzy.Find(query, []string{description, linkText})
-						if len(matches) > 0 && matches[0].Score >= s.MinFuzzyScore {
-							if isLocalURL(url) {
-								return ast.WalkContinue, nil
+					// Find the nearest parent heading
+					category := "n/a"
+					categoryLevel := 0
+					// If we're inside a heading, look for the parent heading
+					if insideHeading {
+						for level := currentLevel - 1; level >= 1; level-- {
+							if parent, ok := headings[level]; ok {
+								category = common.CleanCategory(parent)
+								categoryLevel = level
+								break
 							}
-
-							// Find the nearest parent heading
-							category := "n/a"
-							// If we're inside a heading, look for the parent heading
-							if insideHeading {
-								for level := currentLevel - 1; level >= 1; level-- {
-									if parent, ok := headings[level]; ok {
-										category = common.CleanCategory(parent)
-										break
-									}
-								}
-							} else {
-								// Otherwise, look for the nearest heading
-								for level := currentLevel; level >= 1; level-- {
-									if parent, ok := headings[level]; ok {
-										category = common.CleanCategory(parent)
-										break
-									}
-								}
+						}
+					} else {
+						// Otherwise, look for the nearest heading
+						for level := currentLevel; level >= 1; level-- {
+							if parent, ok := headings[level]; ok {
+								category = common.CleanCategory(parent)
+								categoryLevel = level
+								break
 							}
-
-							sourceMu.Lock()
-							sourceResults = append(sourceResults, Result{
-								URL:         url,
-								Name:        linkText,
-								Description: description,
-								Line:        description,
-								Score:       matches[0].Score,
-								Category:    category,
-								Source:      src.Name,
-							})
-							sourceMu.Unlock()
 						}
 					}
 
-					return ast.WalkContinue, nil
-				})
-
-				return nil
-			})
+					// Collect the headings leading to the category
+					var categoryPath []string
+					for level := 1; level <= categoryLevel; level++ {
+						if heading, ok := headings[level]; ok {
+							categoryPath = append(categoryPath, heading)
+						}
+					}
 
-			if err != nil {
-				log.Error("Error walking source", "source", src.Name, "error", err)
+					line := lineNumber(content, v)
+
+					sourceMu.Lock()
+					sourceResults = append(sourceResults, Result{
+						URL:          url,
+						Name:         linkText,
+						Description:  description,
+						Line:         sourceLine(content, v),
+						Score:        matches[0].Score,
+						Category:     category,
+						Source:       src.Name,
+						SourceType:   string(src.Type),
+						CategoryPath: categoryPath,
+						FilePath:     relPath,
+						LineNumber:   line,
+						SourceURL:    viewSourceURL(string(src.Type), src.URL, "", relPath, line),
+					})
+					sourceMu.Unlock()
+				}
 			}
 
-			resultChan <- sourceResults
-		}(source)
-	}
+			return ast.WalkContinue, nil
+		})
 
-	// Wait for all goroutines to complete
-	go func() {
-		wg.Wait()
-		close(resultChan)
-	}()
-
-	// Collect results from all sources
-	var allResults []Result
-	for results := range resultChan {
-		allResults = append(allResults, results...)
-	}
-
-	// Sort results by score
-	sort.Slice(allResults, func(i, j int) bool {
-		return allResults[i].Score > allResults[j].Score
+		return nil
 	})
 
-	// Normalize scores
-	if len(allResults) > 0 {
-		maxScore := allResults[0].Score
-		for i := range allResults {
-			allResults[i].Score = int((float64(allResults[i].Score) / float64(maxScore)) * 100)
-		}
+	if err != nil && ctx.Err() == nil {
+		log.Error("Error walking source", "source", src.Name, "error", err)
 	}
 
-	return allResults, nil
+	return sourceResults
 }
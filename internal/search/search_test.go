@@ -1,6 +1,7 @@
 package search
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -10,6 +11,9 @@ import (
 
 	"github.com/charmbracelet/log"
 	"github.com/stretchr/testify/assert"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
 )
 
 func TestSearch(t *testing.T) {
@@ -95,7 +99,7 @@ func TestSearch(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			log.Debug("Running test case", "name", tt.name, "query", tt.query)
-			results, err := Search(tt.query, "test", testSettings)
+			results, err := Search(context.Background(), tt.query, "test", testSettings)
 			assert.NoError(t, err)
 			assert.NotNil(t, results)
 
@@ -115,3 +119,117 @@ func TestSearch(t *testing.T) {
 	}
 	log.Debug("All tests completed")
 }
+
+func TestSearchCancelled(t *testing.T) {
+	tmpDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(tmpDir, "test.md"), []byte("## Animals\n* [Koala](https://koala.com/)\n"), 0644)
+	assert.NoError(t, err)
+
+	testSettings := settings.Settings{
+		Sources: []sources.Source{{
+			Name:    "test",
+			Path:    tmpDir,
+			Enabled: true,
+		}},
+		SearchConcurrency: 1,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := Search(ctx, "koala", "", testSettings)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, results)
+}
+
+func TestSearchStream(t *testing.T) {
+	var testSources []sources.Source
+	for _, name := range []string{"first", "second"} {
+		dir := t.TempDir()
+		content := "## Animals\n* [Koala " + name + "](https://" + name + ".koala.com/)\n"
+		err := os.WriteFile(filepath.Join(dir, "test.md"), []byte(content), 0644)
+		assert.NoError(t, err)
+		testSources = append(testSources, sources.Source{Name: name, Path: dir, Enabled: true})
+	}
+
+	testSettings := settings.Settings{
+		Sources:           testSources,
+		SearchConcurrency: 2,
+	}
+
+	batches := make(chan SourceBatch)
+	var seen []string
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for batch := range batches {
+			assert.Len(t, batch.Results, 1)
+			seen = append(seen, batch.Source)
+		}
+	}()
+
+	results, err := SearchStream(context.Background(), "koala", "", testSettings, batches)
+	close(batches)
+	<-done
+
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"first", "second"}, seen)
+	assert.Len(t, results, 2)
+}
+
+func TestSourceLine(t *testing.T) {
+	content := []byte("# Tools\n\nIntro text\n* [Koala](https://koala.com/) - A bear that is not a bear\n")
+	doc := goldmark.New().Parser().Parse(text.NewReader(content))
+
+	var lines []string
+	var numbers []int
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if link, ok := n.(*ast.Link); ok && entering {
+			lines = append(lines, sourceLine(content, link))
+			numbers = append(numbers, lineNumber(content, link))
+		}
+		return ast.WalkContinue, nil
+	})
+
+	assert.Equal(t, []string{"* [Koala](https://koala.com/) - A bear that is not a bear"}, lines)
+	assert.Equal(t, []int{4}, numbers)
+}
+
+func TestSearchLocation(t *testing.T) {
+	tmpDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "docs"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "docs", "animals.md"), []byte("# Animals\n\n* [Koala](https://koala.com/)\n"), 0644))
+
+	s := settings.Settings{Sources: []sources.Source{{
+		Name:    "test",
+		Path:    tmpDir,
+		URL:     "https://github.com/user/animals.git",
+		Type:    sources.SourceTypeGit,
+		Enabled: true,
+	}}}
+
+	results, err := Search(context.Background(), "koala", "", s)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "docs/animals.md", results[0].FilePath)
+		assert.Equal(t, 3, results[0].LineNumber)
+		assert.Equal(t, "https://github.com/user/animals/blob/HEAD/docs/animals.md?plain=1#L3", results[0].SourceURL)
+	}
+}
+
+func TestSearchTableRows(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := "# Editors\n\n| Name | Description | License |\n| --- | --- | --- |\n| [Neovim](https://neovim.io/) | Vim-based editor | Apache-2.0 |\n| [Helix](https://helix-editor.com/) | Modal editor | MPL-2.0 |\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "editors.md"), []byte(content), 0644))
+
+	s := settings.Settings{Sources: []sources.Source{{Name: "test", Path: tmpDir, Type: sources.SourceTypeGit, Enabled: true}}}
+
+	results, err := Search(context.Background(), "modal", "", s)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "https://helix-editor.com/", results[0].URL)
+		assert.Equal(t, "Helix", results[0].Name)
+		assert.Equal(t, "Modal editor", results[0].Description)
+		assert.Equal(t, 6, results[0].LineNumber)
+	}
+}
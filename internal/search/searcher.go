@@ -0,0 +1,599 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"freectl/internal/common"
+	"freectl/internal/preprocessing"
+	"freectl/internal/settings"
+
+	"github.com/charmbracelet/log"
+)
+
+// Sort orders supported by Request.Sort
+const (
+	SortByScore    = "score"
+	SortByName     = "name"
+	SortBySource   = "source"
+	SortByCategory = "category"
+)
+
+// Facet names supported by Request.Facets
+const (
+	FacetSource     = "source"
+	FacetCategory   = "category"
+	FacetTag        = "tag"
+	FacetCollection = "collection"
+	FacetDomain     = "domain"
+	FacetSourceType = "source_type"
+)
+
+// AllFacets lists every facet, in the order frontends display them
+var AllFacets = []string{FacetSource, FacetSourceType, FacetCategory, FacetTag, FacetCollection, FacetDomain}
+
+// How search results with dead links are treated, see Request.DeadLinks
+const (
+	DeadLinksShow   = "show"
+	DeadLinksDemote = "demote"
+	DeadLinksHide   = "hide"
+)
+
+// Request describes a search independently of the backend that runs it
+type Request struct {
+	Query     string        `json:"query"`
+	Filters   SearchFilters `json:"filters"`
+	Page      int           `json:"page"`
+	PerPage   int           `json:"per_page"`             // defaults to Settings.ResultsPerPage
+	Sort      string        `json:"sort"`                 // one of the SortBy constants, defaults to score
+	Facets    []string      `json:"facets"`               // facet names to count, defaults to AllFacets
+	DeadLinks string        `json:"dead_links,omitempty"` // one of the DeadLinks constants, defaults to Settings.DeadLinks
+}
+
+// Response is the filtered, deduplicated, sorted and paginated outcome of a Request
+type Response struct {
+	Results      []Result                `json:"results"`
+	TotalResults int                     `json:"total_results"`
+	TotalPages   int                     `json:"total_pages"`
+	CurrentPage  int                     `json:"current_page"`
+	PerPage      int                     `json:"per_page"`
+	Facets       map[string][]FacetCount `json:"facets"`
+}
+
+// FacetCount is the number of matching results sharing a facet value
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Searcher runs search requests against one kind of backend. Every
+// implementation returns its raw matches through the same filtering, dedup,
+// sorting and pagination pipeline so all frontends see identical results.
+type Searcher interface {
+	// Search runs req and returns one page of results
+	Search(ctx context.Context, req Request) (*Response, error)
+
+	// Name returns a short name for the backend, used in logs
+	Name() string
+}
+
+// StreamingSearcher is implemented by searchers that can report each
+// source's matches before the final ranking across all sources is known
+type StreamingSearcher interface {
+	Searcher
+
+	// SearchStream behaves like Search but also sends each source's results
+	// on batches as soon as they are found. batches is never closed.
+	SearchStream(ctx context.Context, req Request, batches chan<- SourceBatch) (*Response, error)
+}
+
+// NewSearcher returns the searcher selected by the user's settings
+func NewSearcher(s settings.Settings) Searcher {
+	if s.UsePreprocessedSearch {
+		return NewPreprocessedSearcher(s)
+	}
+	return NewLiveSearcher(s)
+}
+
+// LiveSearcher searches the cached markdown files directly
+type LiveSearcher struct {
+	settings settings.Settings
+}
+
+// NewLiveSearcher creates a searcher that parses markdown in real time
+func NewLiveSearcher(s settings.Settings) *LiveSearcher {
+	return &LiveSearcher{settings: s}
+}
+
+// Name returns the name of this searcher
+func (ls *LiveSearcher) Name() string {
+	return "live"
+}
+
+// Search runs req against the cached markdown files
+func (ls *LiveSearcher) Search(ctx context.Context, req Request) (*Response, error) {
+	return ls.SearchStream(ctx, req, nil)
+}
+
+// SearchStream runs req against the cached markdown files, sending each
+// source's results on batches as soon as that source has been walked
+func (ls *LiveSearcher) SearchStream(ctx context.Context, req Request, batches chan<- SourceBatch) (*Response, error) {
+	s := ls.settings
+
+	// Every source is walked even when filtering on some of them, so the
+	// source facet can still count the unselected ones
+	for _, name := range req.Filters.Sources {
+		if !sourceConfigured(s, name) {
+			return nil, fmt.Errorf("source '%s' not found", name)
+		}
+	}
+
+	results, err := SearchStream(ctx, req.Query, "", s, batches)
+	if err != nil {
+		return nil, err
+	}
+
+	// Links are only checked while processing, so look up what was found
+	if deadLinksMode(req, s) != DeadLinksShow {
+		markDeadLinks(results, loadLinkHealth(s.CacheDir))
+	}
+
+	return buildResponse(results, req, s), nil
+}
+
+// PreprocessedSearcher searches the JSON produced by 'freectl process'
+type PreprocessedSearcher struct {
+	settings settings.Settings
+}
+
+// NewPreprocessedSearcher creates a searcher over preprocessed sources
+func NewPreprocessedSearcher(s settings.Settings) *PreprocessedSearcher {
+	return &PreprocessedSearcher{settings: s}
+}
+
+// Name returns the name of this searcher
+func (ps *PreprocessedSearcher) Name() string {
+	return "preprocessed"
+}
+
+// Search runs req against the preprocessed sources
+func (ps *PreprocessedSearcher) Search(ctx context.Context, req Request) (*Response, error) {
+	s := ps.settings
+	ctx, cancel := withSearchTimeout(ctx, s)
+	defer cancel()
+
+	storage := preprocessing.NewFileStorage(filepath.Join(s.CacheDir, "processed"))
+	processedSources, err := storage.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processed sources: %w", err)
+	}
+
+	if len(processedSources) == 0 {
+		return nil, fmt.Errorf("no processed sources found. Run 'freectl process' first")
+	}
+
+	var allItems []SearchableItem
+	matchedSources := 0
+	for _, name := range processedSources {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		processed, err := storage.Load(name)
+		if err != nil {
+			log.Error("Failed to load processed source", "name", name, "error", err)
+			continue
+		}
+
+		// Match filters on the display name rather than the sanitised file name
+		if containsFold(req.Filters.Sources, processed.Source.Name) {
+			matchedSources++
+		}
+
+		allItems = append(allItems, toSearchableItems(processed)...)
+	}
+
+	if matchedSources < len(req.Filters.Sources) {
+		return nil, fmt.Errorf("processed source '%s' not found", strings.Join(req.Filters.Sources, "', '"))
+	}
+
+	results := performFuzzySearch(req.Query, allItems, s)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return buildResponse(results, req, s), nil
+}
+
+// popularityBoost is the score added for every additional list a URL
+// appears in, since links curated by several lists tend to be the better ones
+const popularityBoost = 5
+
+// deadLinkPenalty is the score taken from results whose link is dead when
+// they are demoted rather than hidden
+const deadLinkPenalty = 50
+
+// buildResponse turns raw, score-ordered matches into a Response. This is
+// the single place where merging, filters, sorting and pagination are applied.
+func buildResponse(results []Result, req Request, s settings.Settings) *Response {
+	merged := applyDeadLinks(mergeResults(results), deadLinksMode(req, s))
+	filtered := filterResults(merged, req.Filters)
+	facets := countFacets(merged, req)
+	sortResults(filtered, req.Sort)
+
+	perPage := req.PerPage
+	if perPage < 1 {
+		perPage = s.ResultsPerPage
+	}
+	if perPage < 1 {
+		perPage = 10
+	}
+
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+
+	totalResults := len(filtered)
+	totalPages := 1 // Default to 1 page even with no results
+	if totalResults > 0 {
+		totalPages = (totalResults + perPage - 1) / perPage
+	}
+	if page > totalPages {
+		page = totalPages
+	}
+
+	pageResults := []Result{}
+	if totalResults > 0 {
+		start := (page - 1) * perPage
+		end := start + perPage
+		if end > totalResults {
+			end = totalResults
+		}
+		pageResults = filtered[start:end]
+	}
+
+	return &Response{
+		Results:      pageResults,
+		TotalResults: totalResults,
+		TotalPages:   totalPages,
+		CurrentPage:  page,
+		PerPage:      perPage,
+		Facets:       facets,
+	}
+}
+
+// mergeResults folds results linking to the same page into one, keeping the fields of
+// the best-scoring match and recording where else the URL appeared. Results
+// found in several sources get a popularity boost.
+func mergeResults(results []Result) []Result {
+	index := make(map[string]int)
+	var merged []Result
+	for _, r := range results {
+		appearance := Appearance{Source: r.Source, Category: r.Category, Description: r.Description, SourceURL: r.SourceURL}
+
+		key := common.CanonicalURL(r.URL)
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(merged)
+			r.Appearances = []Appearance{appearance}
+			merged = append(merged, r)
+			continue
+		}
+
+		// Tags and collections of every duplicate are kept, whichever one
+		// scores best
+		m := &merged[i]
+		tags := appendFold(slices.Clone(m.Tags), r.Tags)
+		collections := appendFold(slices.Clone(m.Collections), r.Collections)
+		if r.Score > m.Score {
+			r.Appearances = m.Appearances
+			*m = r
+		}
+		m.Tags = tags
+		m.Collections = collections
+		if !containsAppearance(m.Appearances, appearance) {
+			m.Appearances = append(m.Appearances, appearance)
+		}
+	}
+
+	maxScore := 0
+	for i := range merged {
+		m := &merged[i]
+		m.AppearsIn = len(appearanceSources(*m))
+		m.Score += (m.AppearsIn - 1) * popularityBoost
+		if m.Score > maxScore {
+			maxScore = m.Score
+		}
+	}
+
+	// Bring boosted scores back onto the 0-100 scale
+	if maxScore > 100 {
+		for i := range merged {
+			merged[i].Score = merged[i].Score * 100 / maxScore
+		}
+	}
+
+	return merged
+}
+
+// deadLinksMode returns how the request treats dead links
+func deadLinksMode(req Request, s settings.Settings) string {
+	if req.DeadLinks != "" {
+		return req.DeadLinks
+	}
+	if s.DeadLinks != "" {
+		return s.DeadLinks
+	}
+	return DeadLinksDemote
+}
+
+// applyDeadLinks hides or demotes the results whose link is dead
+func applyDeadLinks(results []Result, mode string) []Result {
+	switch mode {
+	case DeadLinksHide:
+		var alive []Result
+		for _, r := range results {
+			if !r.Dead {
+				alive = append(alive, r)
+			}
+		}
+		return alive
+	case DeadLinksShow:
+		return results
+	default:
+		for i := range results {
+			if results[i].Dead {
+				results[i].Score = max(0, results[i].Score-deadLinkPenalty)
+			}
+		}
+		return results
+	}
+}
+
+// markDeadLinks sets the link status of results from the checked links
+func markDeadLinks(results []Result, health map[string]preprocessing.LinkHealth) {
+	if len(health) == 0 {
+		return
+	}
+	for i := range results {
+		if h, ok := health[common.CanonicalURL(results[i].URL)]; ok {
+			results[i].LinkStatus = h.StatusCode
+			results[i].Dead = h.Dead()
+		}
+	}
+}
+
+// containsAppearance reports whether appearances already holds a
+func containsAppearance(appearances []Appearance, a Appearance) bool {
+	for _, existing := range appearances {
+		if existing == a {
+			return true
+		}
+	}
+	return false
+}
+
+// appearanceSources returns the distinct sources a result was found in
+func appearanceSources(r Result) []string {
+	if len(r.Appearances) == 0 {
+		return []string{r.Source}
+	}
+
+	var names []string
+	for _, a := range r.Appearances {
+		if !containsFold(names, a.Source) {
+			names = append(names, a.Source)
+		}
+	}
+	return names
+}
+
+// appearanceCategories returns the distinct categories a result was found under
+func appearanceCategories(r Result) []string {
+	if len(r.Appearances) == 0 {
+		return []string{r.Category}
+	}
+
+	var names []string
+	for _, a := range r.Appearances {
+		if !containsFold(names, a.Category) {
+			names = append(names, a.Category)
+		}
+	}
+	return names
+}
+
+// filterResults returns the results matching filters
+func filterResults(results []Result, filters SearchFilters) []Result {
+	var filtered []Result
+	for _, r := range results {
+		if filters.Matches(r) {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+// Matches reports whether a result satisfies every filter that is set.
+// Values within one filter are alternatives; separate filters must all match.
+func (f SearchFilters) Matches(r Result) bool {
+	for _, name := range AllFacets {
+		selected := f.values(name)
+		if len(selected) == 0 {
+			continue
+		}
+
+		matches := false
+		for _, value := range facetValues(r, name) {
+			if containsFold(selected, value) {
+				matches = true
+				break
+			}
+		}
+		if !matches {
+			return false
+		}
+	}
+	return r.Score >= f.MinScore
+}
+
+// values returns the selected values of the filter behind a facet
+func (f SearchFilters) values(facet string) []string {
+	switch facet {
+	case FacetSource:
+		return f.Sources
+	case FacetCategory:
+		return f.Categories
+	case FacetTag:
+		return f.Tags
+	case FacetCollection:
+		return f.Collections
+	case FacetDomain:
+		return f.Domains
+	case FacetSourceType:
+		return f.SourceTypes
+	default:
+		return nil
+	}
+}
+
+// without returns a copy of the filters with the facet's selection cleared
+func (f SearchFilters) without(facet string) SearchFilters {
+	switch facet {
+	case FacetSource:
+		f.Sources = nil
+	case FacetCategory:
+		f.Categories = nil
+	case FacetTag:
+		f.Tags = nil
+	case FacetCollection:
+		f.Collections = nil
+	case FacetDomain:
+		f.Domains = nil
+	case FacetSourceType:
+		f.SourceTypes = nil
+	}
+	return f
+}
+
+// sortResults orders results in place; ties keep their score order
+func sortResults(results []Result, sortBy string) {
+	var less func(a, b Result) bool
+	switch sortBy {
+	case SortByName:
+		less = func(a, b Result) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) }
+	case SortBySource:
+		less = func(a, b Result) bool { return strings.ToLower(a.Source) < strings.ToLower(b.Source) }
+	case SortByCategory:
+		less = func(a, b Result) bool { return strings.ToLower(a.Category) < strings.ToLower(b.Category) }
+	default:
+		less = func(a, b Result) bool { return a.Score > b.Score }
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return less(results[i], results[j])
+	})
+}
+
+// countFacets counts the values of each requested facet. Each facet is
+// counted with every filter applied except its own, so selecting a value
+// still shows how many results picking an alternative would add.
+func countFacets(results []Result, req Request) map[string][]FacetCount {
+	names := req.Facets
+	if len(names) == 0 {
+		names = AllFacets
+	}
+
+	facets := make(map[string][]FacetCount)
+	for _, name := range names {
+		counts := make(map[string]int)
+		for _, r := range filterResults(results, req.Filters.without(name)) {
+			for _, value := range facetValues(r, name) {
+				if value != "" {
+					counts[value]++
+				}
+			}
+		}
+
+		values := make([]FacetCount, 0, len(counts))
+		for value, count := range counts {
+			values = append(values, FacetCount{Value: value, Count: count})
+		}
+
+		// Most common first, alphabetical within equal counts
+		sort.Slice(values, func(i, j int) bool {
+			if values[i].Count != values[j].Count {
+				return values[i].Count > values[j].Count
+			}
+			return values[i].Value < values[j].Value
+		})
+		facets[name] = values
+	}
+
+	return facets
+}
+
+// facetValues returns the values a result contributes to a facet
+func facetValues(r Result, name string) []string {
+	switch name {
+	case FacetSource:
+		return appearanceSources(r)
+	case FacetCategory:
+		return appearanceCategories(r)
+	case FacetTag:
+		return r.Tags
+	case FacetCollection:
+		return r.Collections
+	case FacetDomain:
+		return []string{URLDomain(r.URL)}
+	case FacetSourceType:
+		return []string{r.SourceType}
+	default:
+		return nil
+	}
+}
+
+// URLDomain returns the lower-cased host of a URL without any "www." prefix
+func URLDomain(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// sourceConfigured reports whether a source with the given name exists
+func sourceConfigured(s settings.Settings, name string) bool {
+	for _, source := range s.Sources {
+		if strings.EqualFold(source.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendFold appends the values not already in list, ignoring case
+func appendFold(list, values []string) []string {
+	for _, value := range values {
+		if !containsFold(list, value) {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// containsFold reports whether values contains s, ignoring case
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package search
+
+import (
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"freectl/internal/preprocessing"
+	"freectl/internal/settings"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildResponse(t *testing.T) {
+	results := []Result{
+		{Name: "Wiki.js", URL: "https://js.wiki/", Source: "selfhosted", Category: "Wikis", Score: 100},
+		{Name: "Wiki.js", URL: "https://js.wiki/", Source: "other", Category: "Wikis", Score: 90},
+		{Name: "BookStack", URL: "https://bookstackapp.com/", Source: "selfhosted", Category: "Wikis", Score: 80},
+		{Name: "Outline", URL: "https://www.getoutline.com/", Source: "other", Category: "Knowledge", Score: 70},
+	}
+	s := settings.Settings{ResultsPerPage: 2}
+
+	t.Run("merges duplicates", func(t *testing.T) {
+		resp := buildResponse(results, Request{PerPage: 10}, s)
+		wiki := resp.Results[0]
+		assert.Equal(t, "Wiki.js", wiki.Name)
+		assert.Equal(t, "selfhosted", wiki.Source)
+		assert.Equal(t, 2, wiki.AppearsIn)
+		assert.Equal(t, []Appearance{
+			{Source: "selfhosted", Category: "Wikis"},
+			{Source: "other", Category: "Wikis"},
+		}, wiki.Appearances)
+		assert.Equal(t, 1, resp.Results[1].AppearsIn)
+	})
+
+	t.Run("keeps the tags of every duplicate", func(t *testing.T) {
+		tagged := []Result{
+			{URL: "https://js.wiki/", Source: "a", Score: 50, Tags: []string{"wiki"}},
+			{URL: "https://js.wiki/", Source: "b", Score: 60, Tags: []string{"selfhosted"}},
+			{URL: "https://js.wiki/", Source: "c", Score: 90, Tags: []string{"Wiki", "nodejs"}},
+		}
+		resp := buildResponse(tagged, Request{}, s)
+		require.Len(t, resp.Results, 1)
+		assert.Equal(t, "c", resp.Results[0].Source)
+		assert.Equal(t, []string{"wiki", "selfhosted", "nodejs"}, resp.Results[0].Tags)
+	})
+
+	t.Run("ranks popular results higher", func(t *testing.T) {
+		popular := []Result{
+			{Name: "Niche", URL: "https://niche.example/", Source: "a", Score: 100},
+			{Name: "Popular", URL: "https://popular.example/", Source: "a", Score: 98},
+			{Name: "Popular", URL: "https://popular.example/", Source: "b", Score: 90},
+		}
+		resp := buildResponse(popular, Request{}, s)
+		assert.Equal(t, "Popular", resp.Results[0].Name)
+		assert.Equal(t, 100, resp.Results[0].Score)
+	})
+
+	t.Run("dedups and paginates", func(t *testing.T) {
+		resp := buildResponse(results, Request{Page: 2}, s)
+		assert.Equal(t, 3, resp.TotalResults)
+		assert.Equal(t, 2, resp.TotalPages)
+		assert.Equal(t, 2, resp.CurrentPage)
+		assert.Len(t, resp.Results, 1)
+		assert.Equal(t, "Outline", resp.Results[0].Name)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		resp := buildResponse(results, Request{Filters: SearchFilters{Sources: []string{"OTHER"}}}, s)
+		assert.Equal(t, 2, resp.TotalResults)
+		assert.Equal(t, "Wiki.js", resp.Results[0].Name)
+		assert.Equal(t, "Outline", resp.Results[1].Name)
+	})
+
+	t.Run("sorts", func(t *testing.T) {
+		resp := buildResponse(results, Request{Sort: SortByName, PerPage: 10}, s)
+		assert.Equal(t, "BookStack", resp.Results[0].Name)
+		assert.Equal(t, "Wiki.js", resp.Results[2].Name)
+	})
+
+	t.Run("counts facets", func(t *testing.T) {
+		resp := buildResponse(results, Request{Facets: []string{FacetCategory}}, s)
+		assert.Equal(t, []FacetCount{{Value: "Wikis", Count: 2}, {Value: "Knowledge", Count: 1}}, resp.Facets[FacetCategory])
+		assert.NotContains(t, resp.Facets, FacetSource)
+	})
+
+	t.Run("counts every facet by default", func(t *testing.T) {
+		resp := buildResponse(results, Request{}, s)
+		for _, name := range AllFacets {
+			assert.Contains(t, resp.Facets, name)
+		}
+		assert.Contains(t, resp.Facets[FacetDomain], FacetCount{Value: "js.wiki", Count: 1})
+	})
+
+	t.Run("facets ignore their own filter", func(t *testing.T) {
+		filters := SearchFilters{Sources: []string{"other"}, Categories: []string{"Wikis"}}
+		resp := buildResponse(results, Request{Filters: filters}, s)
+		assert.Equal(t, 1, resp.TotalResults)
+		assert.Equal(t, []FacetCount{{Value: "selfhosted", Count: 2}, {Value: "other", Count: 1}}, resp.Facets[FacetSource])
+		assert.Equal(t, []FacetCount{{Value: "Knowledge", Count: 1}, {Value: "Wikis", Count: 1}}, resp.Facets[FacetCategory])
+	})
+
+	t.Run("multi-select filters", func(t *testing.T) {
+		filters := SearchFilters{Domains: []string{"getoutline.com", "bookstackapp.com"}}
+		resp := buildResponse(results, Request{Filters: filters}, s)
+		assert.Equal(t, 2, resp.TotalResults)
+	})
+
+	t.Run("empty page", func(t *testing.T) {
+		resp := buildResponse(nil, Request{}, s)
+		assert.Equal(t, 1, resp.TotalPages)
+		assert.NotNil(t, resp.Results)
+		assert.Empty(t, resp.Results)
+	})
+}
+
+func TestURLDomain(t *testing.T) {
+	assert.Equal(t, "example.com", URLDomain("https://WWW.Example.com:8080/path"))
+	assert.Equal(t, "", URLDomain("not a url"))
+}
+
+func TestApplyDeadLinks(t *testing.T) {
+	results := []Result{
+		{URL: "https://alive.com/", Score: 90},
+		{URL: "https://dead.com/", Score: 80, Dead: true, LinkStatus: 404},
+	}
+
+	hidden := applyDeadLinks(slices.Clone(results), DeadLinksHide)
+	require.Len(t, hidden, 1)
+	assert.Equal(t, "https://alive.com/", hidden[0].URL)
+
+	demoted := applyDeadLinks(slices.Clone(results), DeadLinksDemote)
+	assert.Equal(t, 30, demoted[1].Score)
+
+	assert.Equal(t, results, applyDeadLinks(slices.Clone(results), DeadLinksShow))
+
+	// Dead links are demoted unless the settings or request say otherwise
+	s := settings.DefaultSettings()
+	assert.Equal(t, DeadLinksDemote, deadLinksMode(Request{}, s))
+	s.DeadLinks = DeadLinksHide
+	assert.Equal(t, DeadLinksHide, deadLinksMode(Request{}, s))
+	assert.Equal(t, DeadLinksShow, deadLinksMode(Request{DeadLinks: DeadLinksShow}, s))
+}
+
+func TestLoadLinkHealthCaches(t *testing.T) {
+	cacheDir := t.TempDir()
+	storage := preprocessing.NewFileStorage(filepath.Join(cacheDir, "processed"))
+	checked := time.Now()
+	source := preprocessing.ProcessedSource{
+		Source: preprocessing.SourceMetadata{Name: "demo"},
+		Items: []preprocessing.ProcessedItem{
+			{URL: "https://dead.com/", Health: preprocessing.LinkHealth{StatusCode: 404, CheckedAt: checked}},
+			{URL: "https://unchecked.com/"},
+		},
+	}
+	require.NoError(t, storage.Save(source))
+
+	health := loadLinkHealth(cacheDir)
+	require.Len(t, health, 1)
+	assert.True(t, health["https://dead.com"].Dead())
+
+	// Unchanged files aren't read again
+	again := loadLinkHealth(cacheDir)
+	assert.Equal(t, reflect.ValueOf(health).Pointer(), reflect.ValueOf(again).Pointer())
+
+	source.Items[1].Health = preprocessing.LinkHealth{StatusCode: 200, CheckedAt: checked}
+	require.NoError(t, storage.Save(source))
+	health = loadLinkHealth(cacheDir)
+	assert.Len(t, health, 2)
+}
@@ -16,20 +16,24 @@ import (
 
 // Settings represents the user settings
 type Settings struct {
-	MinQueryLength        int              `json:"minQueryLength"`
-	MaxQueryLength        int              `json:"maxQueryLength"`
-	SearchDelay           int              `json:"searchDelay"`
-	ShowScores            bool             `json:"showScores"`
-	ResultsPerPage        int              `json:"resultsPerPage"`
-	UsePreprocessedSearch bool             `json:"usePreprocessedSearch"`
-	CacheDir              string           `json:"cache_dir"`
-	AutoUpdate            bool             `json:"auto_update"`
-	TruncateTitles        bool             `json:"truncateTitles"`
-	MaxTitleLength        int              `json:"maxTitleLength"`
-	CustomHeader          string           `json:"customHeader"`
-	MinFuzzyScore         int              `json:"minFuzzyScore"`
-	SearchConcurrency     int              `json:"searchConcurrency"`
-	Sources               []sources.Source `json:"sources"`
+	MinQueryLength        int               `json:"minQueryLength"`
+	MaxQueryLength        int               `json:"maxQueryLength"`
+	SearchDelay           int               `json:"searchDelay"`
+	ShowScores            bool              `json:"showScores"`
+	ResultsPerPage        int               `json:"resultsPerPage"`
+	UsePreprocessedSearch bool              `json:"usePreprocessedSearch"`
+	CacheDir              string            `json:"cache_dir"`
+	AutoUpdate            bool              `json:"auto_update"`
+	TruncateTitles        bool              `json:"truncateTitles"`
+	MaxTitleLength        int               `json:"maxTitleLength"`
+	CustomHeader          string            `json:"customHeader"`
+	MinFuzzyScore         int               `json:"minFuzzyScore"`
+	SearchConcurrency     int               `json:"searchConcurrency"`
+	SearchTimeout         int               `json:"searchTimeout"`               // milliseconds, 0 disables the timeout
+	CategoryOverrides     map[string]string `json:"categoryOverrides,omitempty"` // heading to category path, such as "Media > Video"
+	CheckLinks            bool              `json:"checkLinks"`                  // check links while processing sources
+	DeadLinks             string            `json:"deadLinks"`                   // "show", "demote" or "hide" dead links in search results
+	Sources               []sources.Source  `json:"sources"`
 }
 
 // DefaultSettings returns the default settings
@@ -59,6 +63,8 @@ func DefaultSettings() Settings {
 		CustomHeader:          "find cool stuff",
 		MinFuzzyScore:         0, // Default minimum score
 		SearchConcurrency:     1, // Default to 1 for sequential processing
+		SearchTimeout:         10000,
+		DeadLinks:             "demote",
 		Sources:               []sources.Source{},
 	}
 }
@@ -109,7 +115,9 @@ func LoadSettings() (Settings, error) {
 		return DefaultSettings(), nil
 	}
 
-	var settings Settings
+	// Decode on top of the defaults so settings added in newer versions
+	// get sensible values when they are missing from an older config file
+	settings := DefaultSettings()
 	if err := json.Unmarshal(content, &settings); err != nil {
 		// If JSON parsing fails, return default settings but log the error
 		log.Error("Failed to parse settings file", "error", err)
@@ -3,9 +3,12 @@ package sources
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -52,6 +55,83 @@ func GetGitRemoteURL(repoPath string) (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
+// GetGitRevision returns the commit a Git repository is checked out at
+func GetGitRevision(repoPath string) (string, error) {
+	cmd := exec.Command("git", "-C", repoPath, "rev-parse", "HEAD")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to get revision: %s", strings.TrimSpace(string(output)))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GitFileURL returns the web URL showing a line of a file in the upstream
+// repository, or "" when the repository isn't on a known host. revision may
+// be empty to link to the default branch.
+func GitFileURL(repoURL, revision, path string, line int) string {
+	// Turn scp-like remotes such as git@github.com:user/repo.git into URLs
+	if !strings.Contains(repoURL, "://") {
+		if at := strings.Index(repoURL, "@"); at >= 0 {
+			repoURL = "https://" + strings.Replace(repoURL[at+1:], ":", "/", 1)
+		}
+	}
+
+	u, err := url.Parse(repoURL)
+	if err != nil || u.Host == "" || path == "" {
+		return ""
+	}
+	host := strings.ToLower(u.Hostname())
+	base := "https://" + host + strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), ".git")
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	path = strings.Join(segments, "/")
+
+	ref := revision
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	// GitHub and GitLab render markup files, where line anchors go nowhere
+	// unless the source is shown instead
+	var fileURL, anchor string
+	switch {
+	case host == "github.com":
+		fileURL, anchor = base+"/blob/"+ref+"/"+path, plainAnchor(path, "#L")
+	case host == "gitlab.com" || strings.HasPrefix(host, "gitlab."):
+		fileURL, anchor = base+"/-/blob/"+ref+"/"+path, plainAnchor(path, "#L")
+	case host == "bitbucket.org":
+		fileURL, anchor = base+"/src/"+ref+"/"+path, "#lines-"
+	case host == "codeberg.org" || strings.HasPrefix(host, "gitea.") || strings.HasPrefix(host, "forgejo."):
+		// Gitea needs to know whether the ref is a commit or a branch
+		if revision == "" {
+			return ""
+		}
+		fileURL, anchor = base+"/src/commit/"+revision+"/"+path, "#L"
+	default:
+		return ""
+	}
+
+	if line > 0 {
+		fileURL += anchor + strconv.Itoa(line)
+	}
+	return fileURL
+}
+
+// renderedExtensions are the extensions of files GitHub and GitLab render
+var renderedExtensions = []string{".md", ".markdown", ".mdown", ".mkd", ".rst", ".adoc", ".asciidoc", ".org", ".textile", ".rdoc"}
+
+// plainAnchor returns anchor, asking for the plain source first when the
+// file at path would be rendered
+func plainAnchor(path, anchor string) string {
+	if slices.Contains(renderedExtensions, strings.ToLower(filepath.Ext(path))) {
+		return "?plain=1" + anchor
+	}
+	return anchor
+}
+
 // ValidateGitRepo checks if a repository exists and is valid
 func ValidateGitRepo(repoPath string) error {
 	if _, err := os.Stat(repoPath); os.IsNotExist(err) {
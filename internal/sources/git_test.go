@@ -45,6 +45,84 @@ func TestDeriveNameFromURL(t *testing.T) {
 	}
 }
 
+func TestGitFileURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		repoURL  string
+		revision string
+		path     string
+		line     int
+		expected string
+	}{
+		{
+			name:     "github default branch",
+			repoURL:  "https://github.com/fmhy/edit.git",
+			path:     "docs/video.md",
+			line:     123,
+			expected: "https://github.com/fmhy/edit/blob/HEAD/docs/video.md?plain=1#L123",
+		},
+		{
+			name:     "github ssh remote at a revision",
+			repoURL:  "git@github.com:user/repo.git",
+			revision: "abc123",
+			path:     "README.md",
+			line:     4,
+			expected: "https://github.com/user/repo/blob/abc123/README.md?plain=1#L4",
+		},
+		{
+			name:     "github file that isn't rendered",
+			repoURL:  "https://github.com/user/repo",
+			path:     "lists/links.txt",
+			line:     7,
+			expected: "https://github.com/user/repo/blob/HEAD/lists/links.txt#L7",
+		},
+		{
+			name:     "gitlab rendered file",
+			repoURL:  "https://gitlab.com/group/repo.git",
+			path:     "docs/index.rst",
+			line:     9,
+			expected: "https://gitlab.com/group/repo/-/blob/HEAD/docs/index.rst?plain=1#L9",
+		},
+		{
+			name:     "gitlab without line",
+			repoURL:  "https://gitlab.com/group/sub/repo/",
+			path:     "My List.md",
+			expected: "https://gitlab.com/group/sub/repo/-/blob/HEAD/My%20List.md",
+		},
+		{
+			name:     "codeberg needs a revision",
+			repoURL:  "https://codeberg.org/user/repo",
+			path:     "README.md",
+			line:     1,
+			expected: "",
+		},
+		{
+			name:     "codeberg at a revision",
+			repoURL:  "https://codeberg.org/user/repo",
+			revision: "abc123",
+			path:     "README.md",
+			line:     1,
+			expected: "https://codeberg.org/user/repo/src/commit/abc123/README.md#L1",
+		},
+		{
+			name:     "unknown host",
+			repoURL:  "https://git.example.com/user/repo",
+			path:     "README.md",
+			line:     1,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GitFileURL(tt.repoURL, tt.revision, tt.path, tt.line)
+			if result != tt.expected {
+				t.Errorf("GitFileURL(%q, %q, %q, %d) = %q, want %q", tt.repoURL, tt.revision, tt.path, tt.line, result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestValidateGitRepo(t *testing.T) {
 	// Create a temporary directory for testing
 	tmpDir := t.TempDir()
@@ -19,10 +19,12 @@ type CategoryStats struct {
 type Stats struct {
 	TotalFiles    int
 	TotalLinks    int
+	UniqueLinks   int // links to different pages, however their URLs are written
 	TotalSize     int64
 	Categories    []CategoryStats
 	DomainsCount  map[string]int
 	ProtocolStats map[string]int
+	seen          map[string]bool
 	mu            sync.Mutex
 }
 
@@ -109,8 +111,17 @@ func (s *Stats) ProcessFile(path string) {
 		// Look for URLs
 		if strings.Contains(line, "http") || strings.Contains(line, "www.") {
 			if url := common.ExtractURL(line); url != "" {
+				canonical := common.CanonicalURL(url)
+
 				s.mu.Lock()
 				s.TotalLinks++
+				if s.seen == nil {
+					s.seen = make(map[string]bool)
+				}
+				if !s.seen[canonical] {
+					s.seen[canonical] = true
+					s.UniqueLinks++
+				}
 
 				// Update category stats
 				if currentCategory != "" {
@@ -131,7 +142,7 @@ func (s *Stats) ProcessFile(path string) {
 				}
 
 				// Extract and count domains
-				domain := common.ExtractDomain(url)
+				domain := common.ExtractDomain(canonical)
 				if domain != "" {
 					s.DomainsCount[domain]++
 				}
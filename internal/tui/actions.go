@@ -0,0 +1,265 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"freectl/internal/common"
+	"freectl/internal/search"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
+)
+
+var (
+	previewStyle = lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder(), false, false, false, true).
+			BorderForeground(lipgloss.Color("#25A065")).
+			PaddingLeft(2).
+			MarginLeft(1)
+	previewTitleStyle = lipgloss.NewStyle().Bold(true)
+	previewLabelStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#25A065"))
+)
+
+type actionKeyMap struct {
+	toggleFavorite key.Binding
+	copyURL        key.Binding
+	toggleSelect   key.Binding
+	togglePreview  key.Binding
+}
+
+func newActionKeyMap() *actionKeyMap {
+	return &actionKeyMap{
+		toggleFavorite: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "toggle favorite"),
+		),
+		copyURL: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "copy URL"),
+		),
+		toggleSelect: key.NewBinding(
+			key.WithKeys(" ", "x"),
+			key.WithHelp("space", "select"),
+		),
+		togglePreview: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "toggle preview"),
+		),
+	}
+}
+
+// resultActions holds the favorite, selection and preview state shared by
+// the search TUIs. It is kept behind a pointer so copies of a model share it.
+type resultActions struct {
+	keys        *actionKeyMap
+	favorites   map[string]bool // keyed by canonical URL
+	selected    map[string]bool
+	showPreview bool
+
+	width, height int
+}
+
+func newResultActions() *resultActions {
+	favorites := make(map[string]bool)
+	saved, err := search.LoadFavorites()
+	if err != nil {
+		log.Error("Failed to load favorites", "error", err)
+	}
+	for _, f := range saved {
+		favorites[common.CanonicalURL(f.Link)] = true
+	}
+
+	return &resultActions{
+		keys:      newActionKeyMap(),
+		favorites: favorites,
+		selected:  make(map[string]bool),
+	}
+}
+
+// helpKeys lists the action bindings for the list's help view
+func (a *resultActions) helpKeys() []key.Binding {
+	return []key.Binding{a.keys.toggleFavorite, a.keys.copyURL, a.keys.toggleSelect, a.keys.togglePreview}
+}
+
+// newDelegate returns a list delegate that marks selected and favorited results
+func (a *resultActions) newDelegate() list.ItemDelegate {
+	return resultDelegate{DefaultDelegate: list.NewDefaultDelegate(), actions: a}
+}
+
+// resize lays out the list next to the preview pane, if it is shown, within
+// the given space
+func (a *resultActions) resize(l *list.Model, width, height int) {
+	a.width, a.height = width, height
+	if a.showPreview {
+		width = width / 2
+	}
+	l.SetSize(width, height)
+}
+
+// handleKey runs the action bound to msg on the selected result. It reports
+// whether msg was an action key.
+func (a *resultActions) handleKey(msg tea.KeyMsg, l *list.Model) (tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, a.keys.togglePreview):
+		a.showPreview = !a.showPreview
+		a.resize(l, a.width, a.height)
+		return nil, true
+
+	case key.Matches(msg, a.keys.toggleSelect):
+		if result, ok := l.SelectedItem().(SearchResult); ok {
+			if a.selected[result.Link] {
+				delete(a.selected, result.Link)
+			} else {
+				a.selected[result.Link] = true
+			}
+			l.CursorDown()
+		}
+		return nil, true
+
+	case key.Matches(msg, a.keys.toggleFavorite):
+		result, ok := l.SelectedItem().(SearchResult)
+		if !ok {
+			return nil, true
+		}
+		return l.NewStatusMessage(statusMessageStyle(a.toggleFavorite(result))), true
+
+	case key.Matches(msg, a.keys.copyURL):
+		result, ok := l.SelectedItem().(SearchResult)
+		if !ok {
+			return nil, true
+		}
+		if err := copyToClipboard(result.Link); err != nil {
+			log.Error("Failed to copy URL", "url", result.Link, "error", err)
+			return l.NewStatusMessage(statusMessageStyle(fmt.Sprintf("Copy failed: %v", err))), true
+		}
+		return l.NewStatusMessage(statusMessageStyle("Copied " + result.Link)), true
+	}
+
+	return nil, false
+}
+
+// toggleFavorite adds or removes a favorite and describes the outcome
+func (a *resultActions) toggleFavorite(result SearchResult) string {
+	favorite := search.Favorite{
+		Link:        result.Link,
+		Name:        result.Name,
+		Description: result.Summary,
+		Category:    result.Category,
+		Repository:  result.Source,
+	}
+
+	if a.favorites[common.CanonicalURL(result.Link)] {
+		if err := search.RemoveFavorite(favorite); err != nil {
+			log.Error("Failed to remove favorite", "url", result.Link, "error", err)
+			return fmt.Sprintf("Failed to remove favorite: %v", err)
+		}
+		delete(a.favorites, common.CanonicalURL(result.Link))
+		return "Removed from favorites"
+	}
+
+	if err := search.AddFavorite(favorite); err != nil {
+		log.Error("Failed to add favorite", "url", result.Link, "error", err)
+		return fmt.Sprintf("Failed to add favorite: %v", err)
+	}
+	a.favorites[common.CanonicalURL(result.Link)] = true
+	return "Added to favorites"
+}
+
+// openResults opens every selected result, or the highlighted one when
+// nothing is selected, and clears the selection
+func (a *resultActions) openResults(l *list.Model) tea.Cmd {
+	if len(a.selected) == 0 {
+		if result, ok := l.SelectedItem().(SearchResult); ok {
+			openBrowser(result.Link)
+		}
+		return nil
+	}
+
+	// Open in list order rather than map order
+	opened := 0
+	for _, item := range l.Items() {
+		if result, ok := item.(SearchResult); ok && a.selected[result.Link] {
+			openBrowser(result.Link)
+			opened++
+		}
+	}
+	a.selected = make(map[string]bool)
+	return l.NewStatusMessage(statusMessageStyle(fmt.Sprintf("Opened %d results", opened)))
+}
+
+// view renders the list, with the preview pane beside it when shown
+func (a *resultActions) view(l list.Model) string {
+	if !a.showPreview {
+		return l.View()
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, l.View(), a.previewView(l))
+}
+
+// previewView renders the details of the highlighted result
+func (a *resultActions) previewView(l list.Model) string {
+	width := a.width - l.Width() - previewStyle.GetHorizontalFrameSize()
+	style := previewStyle.Width(max(width, 0)).Height(l.Height())
+
+	result, ok := l.SelectedItem().(SearchResult)
+	if !ok {
+		return style.Render("Nothing selected")
+	}
+
+	sources := result.Sources
+	if len(sources) == 0 {
+		sources = []string{result.Source}
+	}
+	categoryPath := strings.Join(result.CategoryPath, " › ")
+	if categoryPath == "" {
+		categoryPath = result.Category
+	}
+
+	var b strings.Builder
+	b.WriteString(previewTitleStyle.Render(result.Name) + "\n")
+	b.WriteString(result.Link + "\n\n")
+	if result.Summary != "" {
+		b.WriteString(result.Summary + "\n\n")
+	}
+	fmt.Fprintf(&b, "%s %s\n", previewLabelStyle.Render("Category:"), categoryPath)
+	fmt.Fprintf(&b, "%s %s\n", previewLabelStyle.Render("Source:"), strings.Join(sources, ", "))
+	if a.favorites[common.CanonicalURL(result.Link)] {
+		fmt.Fprintf(&b, "%s yes\n", previewLabelStyle.Render("Favorite:"))
+	}
+	if result.Line != "" {
+		fmt.Fprintf(&b, "\n%s\n%s\n", previewLabelStyle.Render("Source line:"), result.Line)
+	}
+
+	return style.Render(b.String())
+}
+
+// marker returns the prefix flagging a result as selected or favorited
+func (a *resultActions) marker(link string) string {
+	var marker string
+	if a.selected[link] {
+		marker += "✓ "
+	}
+	if a.favorites[common.CanonicalURL(link)] {
+		marker += "★ "
+	}
+	return marker
+}
+
+// resultDelegate renders results like the default delegate, prefixed with
+// their selection and favorite markers
+type resultDelegate struct {
+	list.DefaultDelegate
+	actions *resultActions
+}
+
+func (d resultDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if result, ok := item.(SearchResult); ok {
+		result.marker = d.actions.marker(result.Link)
+		item = result
+	}
+	d.DefaultDelegate.Render(w, m, index, item)
+}
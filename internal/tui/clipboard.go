@@ -0,0 +1,36 @@
+package tui
+
+import (
+	"os"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// copyToClipboard copies text to the system clipboard. Over SSH, or where no
+// clipboard tool is available, it asks the terminal to do it with an OSC 52
+// escape sequence instead, which reaches the clipboard of the local machine.
+func copyToClipboard(text string) error {
+	if os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != "" || clipboard.Unsupported {
+		return copyWithOSC52(text)
+	}
+
+	if err := clipboard.WriteAll(text); err != nil {
+		return copyWithOSC52(text)
+	}
+	return nil
+}
+
+// copyWithOSC52 writes an OSC 52 sequence, wrapped for terminal multiplexers
+func copyWithOSC52(text string) error {
+	seq := osc52.New(text)
+	if os.Getenv("TMUX") != "" {
+		seq = seq.Tmux()
+	} else if strings.HasPrefix(os.Getenv("TERM"), "screen") {
+		seq = seq.Screen()
+	}
+
+	_, err := seq.WriteTo(os.Stderr)
+	return err
+}
@@ -0,0 +1,272 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var inputStyle = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder()).
+	BorderForeground(lipgloss.Color("#25A065")).
+	Padding(0, 1).
+	MarginBottom(1)
+
+// SearchFunc runs a search for the live search TUI. It is called with a
+// fresh context for every query, which is cancelled once the query is stale.
+type SearchFunc func(ctx context.Context, query string) ([]SearchResult, error)
+
+// debounceMsg fires once typing has paused for the search delay
+type debounceMsg struct {
+	seq int
+}
+
+// liveResultsMsg carries the results of the search for one query
+type liveResultsMsg struct {
+	seq     int
+	results []SearchResult
+	err     error
+}
+
+type liveKeyMap struct {
+	switchFocus key.Binding
+	openURL     key.Binding
+	quit        key.Binding
+}
+
+func newLiveKeyMap() *liveKeyMap {
+	return &liveKeyMap{
+		switchFocus: key.NewBinding(
+			key.WithKeys("tab", "shift+tab"),
+			key.WithHelp("tab", "switch between query and results"),
+		),
+		openURL: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "open selected URLs"),
+		),
+		quit: key.NewBinding(
+			key.WithKeys("esc", "ctrl+c"),
+			key.WithHelp("esc", "quit"),
+		),
+	}
+}
+
+type liveModel struct {
+	input     textinput.Model
+	list      list.Model
+	keys      *liveKeyMap
+	actions   *resultActions
+	search    SearchFunc
+	delay     time.Duration
+	minLength int
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	seq    int
+}
+
+// NewLiveModel creates a search TUI with a query input above the results.
+// The search re-runs once typing has paused for delay, and queries shorter
+// than minLength clear the results instead of searching. ctx bounds every
+// search the model starts.
+func NewLiveModel(ctx context.Context, search SearchFunc, query string, delay time.Duration, minLength int) liveModel {
+	input := textinput.New()
+	input.Placeholder = "Search data sources..."
+	input.Prompt = "🔍 "
+	input.SetValue(query)
+	input.Focus()
+
+	keys := newLiveKeyMap()
+	actions := newResultActions()
+
+	l := list.New(nil, actions.newDelegate(), 0, 0)
+	l.Title = "Search Results"
+	l.Styles.Title = titleStyle
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.SetShowPagination(true)
+	l.SetShowTitle(true)
+
+	// Typing must reach the input, so only non-printable keys move the list
+	l.KeyMap.CursorUp = key.NewBinding(key.WithKeys("up", "ctrl+p"), key.WithHelp("↑", "up"))
+	l.KeyMap.CursorDown = key.NewBinding(key.WithKeys("down", "ctrl+n"), key.WithHelp("↓", "down"))
+	l.KeyMap.PrevPage.SetKeys("pgup")
+	l.KeyMap.NextPage.SetKeys("pgdown")
+	l.KeyMap.GoToStart.SetKeys("home")
+	l.KeyMap.GoToEnd.SetKeys("end")
+	l.KeyMap.Quit.SetEnabled(false)
+	l.KeyMap.ForceQuit.SetEnabled(false)
+	l.KeyMap.ShowFullHelp.SetEnabled(false)
+	l.KeyMap.CloseFullHelp.SetEnabled(false)
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return append([]key.Binding{keys.switchFocus, keys.openURL}, actions.helpKeys()...)
+	}
+
+	return liveModel{
+		input:     input,
+		list:      l,
+		keys:      keys,
+		actions:   actions,
+		search:    search,
+		delay:     delay,
+		minLength: minLength,
+		ctx:       ctx,
+	}
+}
+
+func (m liveModel) Init() tea.Cmd {
+	cmds := []tea.Cmd{textinput.Blink}
+	if m.input.Value() != "" {
+		cmds = append(cmds, m.debounce())
+	}
+	return tea.Batch(cmds...)
+}
+
+// debounce schedules a search for the current query after the delay
+func (m liveModel) debounce() tea.Cmd {
+	seq := m.seq
+	return tea.Tick(m.delay, func(time.Time) tea.Msg {
+		return debounceMsg{seq: seq}
+	})
+}
+
+// runSearch cancels any search still running and starts one for the
+// current query
+func (m *liveModel) runSearch() tea.Cmd {
+	if m.cancel != nil {
+		m.cancel()
+	}
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.cancel = cancel
+	m.list.Title = "Searching..."
+
+	seq, query, search := m.seq, strings.TrimSpace(m.input.Value()), m.search
+	return func() tea.Msg {
+		results, err := search(ctx, query)
+		return liveResultsMsg{seq: seq, results: results, err: err}
+	}
+}
+
+// setResults replaces the list contents with results
+func (m *liveModel) setResults(results []SearchResult) tea.Cmd {
+	items := make([]list.Item, len(results))
+	for i, result := range results {
+		items[i] = result
+	}
+	m.list.ResetSelected()
+	return m.list.SetItems(items)
+}
+
+func (m liveModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		h, v := appStyle.GetFrameSize()
+		m.input.Width = msg.Width - h - inputStyle.GetHorizontalFrameSize() - lipgloss.Width(m.input.Prompt) - 1
+		m.actions.resize(&m.list, msg.Width-h, msg.Height-v-lipgloss.Height(m.inputView()))
+		return m, nil
+
+	case debounceMsg:
+		// Ignore pauses that were followed by more typing
+		if msg.seq != m.seq {
+			return m, nil
+		}
+		if len(strings.TrimSpace(m.input.Value())) < m.minLength {
+			if m.cancel != nil {
+				m.cancel()
+			}
+			m.list.Title = "Search Results"
+			return m, m.setResults(nil)
+		}
+		return m, m.runSearch()
+
+	case liveResultsMsg:
+		// Results for an older query arrive after a newer one was typed
+		if msg.seq != m.seq {
+			return m, nil
+		}
+		m.list.Title = "Search Results"
+		if msg.err != nil {
+			if m.ctx.Err() != nil || errors.Is(msg.err, context.Canceled) {
+				return m, nil
+			}
+			return m, m.list.NewStatusMessage(statusMessageStyle(fmt.Sprintf("Search failed: %v", msg.err)))
+		}
+		cmd := m.setResults(msg.results)
+		if len(msg.results) == 0 {
+			return m, tea.Batch(cmd, m.list.NewStatusMessage(statusMessageStyle("No results found")))
+		}
+		return m, cmd
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.quit):
+			if m.cancel != nil {
+				m.cancel()
+			}
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.switchFocus):
+			if m.input.Focused() {
+				m.input.Blur()
+			} else {
+				return m, m.input.Focus()
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.openURL):
+			return m, m.actions.openResults(&m.list)
+		}
+
+		// Result actions use plain letters, so they only apply while the
+		// results have focus
+		if !m.input.Focused() {
+			if cmd, ok := m.actions.handleKey(msg, &m.list); ok {
+				return m, cmd
+			}
+		}
+
+		// Typing while the results have focus goes back to the query
+		if !m.input.Focused() && msg.Type == tea.KeyRunes {
+			m.input.Focus()
+		}
+
+		// Keys edit the query while the input has focus, except the ones
+		// that move through the results
+		if m.input.Focused() && !m.isListNavigation(msg) {
+			before := m.input.Value()
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			if m.input.Value() != before {
+				m.seq++
+				return m, tea.Batch(cmd, m.debounce())
+			}
+			return m, cmd
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+// isListNavigation reports whether a key moves through the results
+func (m liveModel) isListNavigation(msg tea.KeyMsg) bool {
+	keys := m.list.KeyMap
+	return key.Matches(msg, keys.CursorUp, keys.CursorDown, keys.PrevPage, keys.NextPage)
+}
+
+// inputView renders the query input
+func (m liveModel) inputView() string {
+	return inputStyle.Render(m.input.View())
+}
+
+func (m liveModel) View() string {
+	return appStyle.Render(lipgloss.JoinVertical(lipgloss.Left, m.inputView(), m.actions.view(m.list)))
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os/exec"
 	"runtime"
+	"sort"
 	"strings"
 	"syscall"
 
@@ -30,17 +31,24 @@ var (
 )
 
 type SearchResult struct {
-	Category  string
-	Link      string
-	Name      string
-	Line      string
-	Score     int
-	Source    string
-	IsInvalid bool
+	Category     string
+	CategoryPath []string
+	Link         string
+	Name         string
+	Summary      string // the full description, shown in the preview pane
+	Line         string
+	Score        int
+	Source       string
+	Sources      []string // every source a merged result appeared in
+	AppearsIn    int
+	IsInvalid    bool
+
+	// marker flags the result as selected or favorited when rendered
+	marker string
 }
 
 func (i SearchResult) Title() string {
-	text := i.Name
+	text := i.marker + i.Name
 	if i.IsInvalid {
 		text = "⚠️ " + text
 		return invalidStyle.Render(text)
@@ -53,7 +61,11 @@ func (i SearchResult) Description() string {
 	if !i.IsInvalid {
 		categoryName = i.Category
 	}
-	return fmt.Sprintf("Category: %s | Source: %s | URL: %s | Score: %d", categoryName, i.Source, i.Link, i.Score)
+	description := fmt.Sprintf("Category: %s | Source: %s | URL: %s | Score: %d", categoryName, i.Source, i.Link, i.Score)
+	if i.AppearsIn > 1 {
+		description += fmt.Sprintf(" | In %d lists", i.AppearsIn)
+	}
+	return description
 }
 
 func (i SearchResult) FilterValue() string {
@@ -74,7 +86,7 @@ func newListKeyMap() *listKeyMap {
 		),
 		openURL: key.NewBinding(
 			key.WithKeys("enter", "o"),
-			key.WithHelp("enter/o", "open URL"),
+			key.WithHelp("enter/o", "open selected URLs"),
 		),
 		quit: key.NewBinding(
 			key.WithKeys("q", "ctrl+c"),
@@ -83,10 +95,26 @@ func newListKeyMap() *listKeyMap {
 	}
 }
 
+// ResultsBatchMsg adds provisional results from a single source while an
+// incremental search is still running
+type ResultsBatchMsg struct {
+	Results []SearchResult
+}
+
+// ResultsDoneMsg replaces any provisional results with the final ranked list
+// once an incremental search has finished
+type ResultsDoneMsg struct {
+	Results []SearchResult
+	Err     error
+}
+
 type model struct {
-	list    list.Model
-	keys    *listKeyMap
-	results []SearchResult
+	list      list.Model
+	keys      *listKeyMap
+	actions   *resultActions
+	results   []SearchResult
+	limit     int
+	searching bool
 }
 
 func NewModel(results []SearchResult) model {
@@ -95,7 +123,10 @@ func NewModel(results []SearchResult) model {
 		items[i] = result
 	}
 
-	l := list.New(items, list.NewDefaultDelegate(), 0, 0)
+	keys := newListKeyMap()
+	actions := newResultActions()
+
+	l := list.New(items, actions.newDelegate(), 0, 0)
 	l.Title = "Search Results"
 	l.Styles.Title = titleStyle
 	l.SetShowStatusBar(false)
@@ -103,14 +134,49 @@ func NewModel(results []SearchResult) model {
 	l.SetShowHelp(false)
 	l.SetShowPagination(true)
 	l.SetShowTitle(true)
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return append([]key.Binding{keys.openURL}, actions.helpKeys()...)
+	}
+
+	// f toggles favorites instead of paging
+	l.KeyMap.NextPage.SetKeys("right", "l", "pgdown", "d")
 
 	return model{
 		list:    l,
-		keys:    newListKeyMap(),
+		keys:    keys,
+		actions: actions,
 		results: results,
 	}
 }
 
+// NewIncrementalModel creates a model that starts empty and is filled by
+// ResultsBatchMsg and ResultsDoneMsg messages sent to the program, keeping at
+// most limit results on screen
+func NewIncrementalModel(limit int) model {
+	m := NewModel(nil)
+	m.limit = limit
+	m.searching = true
+	m.list.Title = "Searching..."
+	return m
+}
+
+// setResults replaces the list contents, keeping the best results first
+func (m *model) setResults(results []SearchResult) tea.Cmd {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if m.limit > 0 && len(results) > m.limit {
+		results = results[:m.limit]
+	}
+	m.results = results
+
+	items := make([]list.Item, len(results))
+	for i, result := range results {
+		items[i] = result
+	}
+	return m.list.SetItems(items)
+}
+
 func (m model) Init() tea.Cmd {
 	return nil
 }
@@ -121,7 +187,23 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		h, v := appStyle.GetFrameSize()
-		m.list.SetSize(msg.Width-h, msg.Height-v)
+		m.actions.resize(&m.list, msg.Width-h, msg.Height-v)
+
+	case ResultsBatchMsg:
+		merged := append(append([]SearchResult{}, m.results...), msg.Results...)
+		return m, m.setResults(merged)
+
+	case ResultsDoneMsg:
+		m.searching = false
+		m.list.Title = "Search Results"
+		if msg.Err != nil {
+			return m, m.list.NewStatusMessage(statusMessageStyle(fmt.Sprintf("Search failed: %v", msg.Err)))
+		}
+		cmd := m.setResults(msg.Results)
+		if len(msg.Results) == 0 {
+			return m, tea.Batch(cmd, m.list.NewStatusMessage(statusMessageStyle("No results found")))
+		}
+		return m, cmd
 
 	case tea.KeyMsg:
 		if key.Matches(msg, m.keys.toggleHelpMenu) {
@@ -129,11 +211,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		if key.Matches(msg, m.keys.openURL) {
-			selectedItem := m.list.SelectedItem()
-			if result, ok := selectedItem.(SearchResult); ok {
-				openBrowser(result.Link)
-			}
-			return m, nil
+			return m, m.actions.openResults(&m.list)
+		}
+		if cmd, ok := m.actions.handleKey(msg, &m.list); ok {
+			return m, cmd
 		}
 		if key.Matches(msg, m.keys.quit) {
 			return m, tea.Quit
@@ -148,18 +229,23 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m model) View() string {
-	return appStyle.Render(m.list.View())
+	return appStyle.Render(m.actions.view(m.list))
 }
 
-// openBrowser opens the URL in the default browser with better error handling
+// openBrowser opens the URL in the default browser, logging any failure
 func openBrowser(url string) {
+	if err := OpenBrowser(url); err != nil {
+		log.Error("Failed to open URL", "url", url, "error", err)
+	}
+}
+
+// OpenBrowser opens the URL in the default browser
+func OpenBrowser(url string) error {
 	var cmd *exec.Cmd
-	var err error
 
 	// Validate URL
 	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "www.") {
-		log.Error("Invalid URL format", "url", url)
-		return
+		return fmt.Errorf("invalid URL format: %s", url)
 	}
 
 	// Add https:// prefix if missing
@@ -175,8 +261,7 @@ func openBrowser(url string) {
 	case "darwin":
 		cmd = exec.Command("open", url)
 	default:
-		log.Error("Unsupported platform", "os", runtime.GOOS)
-		return
+		return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
 	}
 
 	// Set process group ID to allow killing the browser process if needed
@@ -187,13 +272,13 @@ func openBrowser(url string) {
 	}
 
 	// Start the browser process
-	if err = cmd.Start(); err != nil {
-		log.Error("Failed to open URL", "url", url, "error", err)
-		return
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start browser: %w", err)
 	}
 
 	// Detach the process
-	if err = cmd.Process.Release(); err != nil {
+	if err := cmd.Process.Release(); err != nil {
 		log.Error("Failed to release browser process", "error", err)
 	}
+	return nil
 }
@@ -0,0 +1,576 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"freectl/internal/settings"
+	"freectl/internal/sources"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	formStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("#25A065")).
+			Padding(1, 2)
+	formLabelStyle    = lipgloss.NewStyle().Bold(true)
+	formHintStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("#777777"))
+	formSelectedStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#FFFDF5")).
+				Background(lipgloss.Color("#25A065")).
+				Padding(0, 1)
+	formOptionStyle = lipgloss.NewStyle().Padding(0, 1)
+	disabledStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#777777"))
+)
+
+// addSourceTypes are the source types that can be added from the form
+var addSourceTypes = []sources.SourceType{
+	sources.SourceTypeGit,
+	sources.SourceTypeRedditWiki,
+	sources.SourceTypeHN5000,
+	sources.SourceTypeHTML,
+}
+
+// sourcesMode is the screen the source manager is showing
+type sourcesMode int
+
+const (
+	sourcesModeList sourcesMode = iota
+	sourcesModeRename
+	sourcesModeDelete
+	sourcesModeAdd
+)
+
+// Fields of the add form, in tab order
+const (
+	addFieldURL = iota
+	addFieldName
+	addFieldType
+	addFieldCount
+)
+
+// sourcesLoadedMsg carries the sources read from the settings
+type sourcesLoadedMsg struct {
+	sources []sources.Source
+	err     error
+}
+
+// sourceDoneMsg reports the outcome of a background operation on a source
+type sourceDoneMsg struct {
+	name   string
+	action string
+	err    error
+}
+
+// sourceItem is a source as shown in the list
+type sourceItem struct {
+	source sources.Source
+	status string
+}
+
+func (i sourceItem) Title() string {
+	if i.source.Enabled {
+		return "● " + i.source.Name
+	}
+	return disabledStyle.Render("○ " + i.source.Name + " (disabled)")
+}
+
+func (i sourceItem) Description() string {
+	parts := []string{string(i.source.Type)}
+	if i.source.Size != "" {
+		parts = append(parts, i.source.Size)
+	}
+	if i.source.LastUpdated != "" {
+		parts = append(parts, "updated "+i.source.LastUpdated)
+	}
+	if i.status != "" {
+		parts = append(parts, i.status)
+	}
+	return strings.Join(parts, " | ")
+}
+
+func (i sourceItem) FilterValue() string {
+	return i.source.Name
+}
+
+type sourcesKeyMap struct {
+	toggle  key.Binding
+	rename  key.Binding
+	delete  key.Binding
+	update  key.Binding
+	add     key.Binding
+	confirm key.Binding
+	cancel  key.Binding
+	next    key.Binding
+	prev    key.Binding
+	quit    key.Binding
+}
+
+func newSourcesKeyMap() *sourcesKeyMap {
+	return &sourcesKeyMap{
+		toggle: key.NewBinding(
+			key.WithKeys(" ", "t"),
+			key.WithHelp("space", "enable/disable"),
+		),
+		rename: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "rename"),
+		),
+		delete: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "delete"),
+		),
+		update: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "update"),
+		),
+		add: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "add"),
+		),
+		confirm: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "confirm"),
+		),
+		cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+		),
+		next: key.NewBinding(
+			key.WithKeys("tab", "down"),
+			key.WithHelp("tab", "next field"),
+		),
+		prev: key.NewBinding(
+			key.WithKeys("shift+tab", "up"),
+			key.WithHelp("shift+tab", "previous field"),
+		),
+		quit: key.NewBinding(
+			key.WithKeys("q", "ctrl+c"),
+			key.WithHelp("q", "quit"),
+		),
+	}
+}
+
+// sourcesState is shared between the model and its list delegate, which
+// renders the progress of running operations
+type sourcesState struct {
+	busy    map[string]string // source name to the running action
+	errors  map[string]string // source name to the last failure
+	spinner spinner.Model
+}
+
+type sourcesModel struct {
+	list  list.Model
+	keys  *sourcesKeyMap
+	state *sourcesState
+	mode  sourcesMode
+
+	// rename and delete target
+	target string
+	rename textinput.Model
+
+	// add form
+	addInputs []textinput.Model
+	addType   int
+	addField  int
+	addError  string
+}
+
+// NewSourcesModel creates the full-screen source manager
+func NewSourcesModel() sourcesModel {
+	keys := newSourcesKeyMap()
+	state := &sourcesState{
+		busy:    make(map[string]string),
+		errors:  make(map[string]string),
+		spinner: spinner.New(spinner.WithSpinner(spinner.Dot)),
+	}
+
+	l := list.New(nil, sourceDelegate{DefaultDelegate: list.NewDefaultDelegate(), state: state}, 0, 0)
+	l.Title = "Data Sources"
+	l.Styles.Title = titleStyle
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.KeyMap.Quit.SetEnabled(false)
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.toggle, keys.update, keys.add, keys.rename, keys.delete}
+	}
+
+	rename := textinput.New()
+	rename.Prompt = "New name: "
+
+	url := textinput.New()
+	url.Placeholder = "https://github.com/awesome-selfhosted/awesome-selfhosted"
+	name := textinput.New()
+	name.Placeholder = "derived from the URL for git sources"
+
+	return sourcesModel{
+		list:      l,
+		keys:      keys,
+		state:     state,
+		rename:    rename,
+		addInputs: []textinput.Model{url, name},
+	}
+}
+
+func (m sourcesModel) Init() tea.Cmd {
+	return tea.Batch(loadSources, m.state.spinner.Tick)
+}
+
+// loadSources reads the sources from the settings
+func loadSources() tea.Msg {
+	sourceList, err := settings.ListSources()
+	return sourcesLoadedMsg{sources: sourceList, err: err}
+}
+
+// runSourceAction runs a slow operation on a source in the background
+func runSourceAction(name, action string, fn func() error) tea.Cmd {
+	return func() tea.Msg {
+		return sourceDoneMsg{name: name, action: action, err: fn()}
+	}
+}
+
+// selectedSource returns the highlighted source
+func (m sourcesModel) selectedSource() (sources.Source, bool) {
+	item, ok := m.list.SelectedItem().(sourceItem)
+	return item.source, ok
+}
+
+// status shows a message below the list title
+func (m *sourcesModel) status(format string, args ...interface{}) tea.Cmd {
+	return m.list.NewStatusMessage(statusMessageStyle(fmt.Sprintf(format, args...)))
+}
+
+func (m sourcesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		h, v := appStyle.GetFrameSize()
+		m.list.SetSize(msg.Width-h, msg.Height-v)
+		return m, nil
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.state.spinner, cmd = m.state.spinner.Update(msg)
+		return m, cmd
+
+	case sourcesLoadedMsg:
+		if msg.err != nil {
+			return m, m.status("Failed to load sources: %v", msg.err)
+		}
+		items := make([]list.Item, len(msg.sources))
+		for i, source := range msg.sources {
+			items[i] = sourceItem{source: source}
+		}
+		return m, m.list.SetItems(items)
+
+	case sourceDoneMsg:
+		delete(m.state.busy, msg.name)
+		if msg.err != nil {
+			m.state.errors[msg.name] = msg.err.Error()
+			return m, tea.Batch(loadSources, m.status("Failed to %s %s: %v", msg.action, msg.name, msg.err))
+		}
+		delete(m.state.errors, msg.name)
+		return m, tea.Batch(loadSources, m.status("Finished %s %s", progressive(msg.action), msg.name))
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case sourcesModeRename:
+			return m.updateRename(msg)
+		case sourcesModeDelete:
+			return m.updateDelete(msg)
+		case sourcesModeAdd:
+			return m.updateAdd(msg)
+		}
+
+		if cmd, ok := m.handleListKey(msg); ok {
+			return m, cmd
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+// handleListKey runs the action bound to msg on the highlighted source. It
+// reports whether msg was an action key.
+func (m *sourcesModel) handleListKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, m.keys.quit):
+		return tea.Quit, true
+
+	case key.Matches(msg, m.keys.add):
+		m.mode = sourcesModeAdd
+		m.addField = addFieldURL
+		m.addType = 0
+		m.addError = ""
+		for i := range m.addInputs {
+			m.addInputs[i].SetValue("")
+		}
+		return m.focusAddField(), true
+	}
+
+	source, ok := m.selectedSource()
+	if !ok {
+		return nil, false
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.toggle):
+		if err := settings.ToggleSourceEnabled(source.Name); err != nil {
+			return m.status("Failed to toggle %s: %v", source.Name, err), true
+		}
+		return loadSources, true
+
+	case key.Matches(msg, m.keys.update):
+		if _, busy := m.state.busy[source.Name]; busy {
+			return nil, true
+		}
+		m.state.busy[source.Name] = "updating"
+		return runSourceAction(source.Name, "update", func() error {
+			return settings.UpdateSource(source.Name)
+		}), true
+
+	case key.Matches(msg, m.keys.rename):
+		m.mode = sourcesModeRename
+		m.target = source.Name
+		m.rename.SetValue(source.Name)
+		m.rename.CursorEnd()
+		return m.rename.Focus(), true
+
+	case key.Matches(msg, m.keys.delete):
+		m.mode = sourcesModeDelete
+		m.target = source.Name
+		return nil, true
+	}
+
+	return nil, false
+}
+
+func (m sourcesModel) updateRename(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.cancel):
+		m.mode = sourcesModeList
+		m.rename.Blur()
+		return m, nil
+
+	case key.Matches(msg, m.keys.confirm):
+		m.mode = sourcesModeList
+		m.rename.Blur()
+		newName := strings.TrimSpace(m.rename.Value())
+		if newName == "" || newName == m.target {
+			return m, nil
+		}
+		if err := settings.RenameSource(m.target, newName); err != nil {
+			return m, m.status("Failed to rename %s: %v", m.target, err)
+		}
+		return m, tea.Batch(loadSources, m.status("Renamed %s to %s", m.target, newName))
+	}
+
+	var cmd tea.Cmd
+	m.rename, cmd = m.rename.Update(msg)
+	return m, cmd
+}
+
+func (m sourcesModel) updateDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		m.mode = sourcesModeList
+		name := m.target
+		m.state.busy[name] = "deleting"
+		return m, runSourceAction(name, "delete", func() error {
+			return deleteSource(name)
+		})
+	case "n", "N", "esc", "q":
+		m.mode = sourcesModeList
+	}
+	return m, nil
+}
+
+// deleteSource removes a source from the settings and the cache, like the
+// web interface does
+func deleteSource(name string) error {
+	s, err := settings.LoadSettings()
+	if err != nil {
+		return fmt.Errorf("failed to load settings: %w", err)
+	}
+
+	if err := settings.DeleteSource(name, true); err != nil {
+		return fmt.Errorf("failed to delete source from settings: %w", err)
+	}
+
+	// The source is already gone from the settings, so a leftover cache
+	// directory is not worth failing over
+	_ = sources.Delete(s.CacheDir, name, false)
+	return nil
+}
+
+func (m sourcesModel) updateAdd(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.cancel):
+		m.mode = sourcesModeList
+		return m, m.focusAddField()
+
+	case key.Matches(msg, m.keys.next):
+		m.addField = (m.addField + 1) % addFieldCount
+		return m, m.focusAddField()
+
+	case key.Matches(msg, m.keys.prev):
+		m.addField = (m.addField + addFieldCount - 1) % addFieldCount
+		return m, m.focusAddField()
+
+	case key.Matches(msg, m.keys.confirm):
+		if m.addField != addFieldType {
+			m.addField++
+			return m, m.focusAddField()
+		}
+		return m.submitAdd()
+	}
+
+	if m.addField == addFieldType {
+		switch msg.String() {
+		case "left", "h":
+			m.addType = (m.addType + len(addSourceTypes) - 1) % len(addSourceTypes)
+		case "right", "l":
+			m.addType = (m.addType + 1) % len(addSourceTypes)
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.addInputs[m.addField], cmd = m.addInputs[m.addField].Update(msg)
+	return m, cmd
+}
+
+// focusAddField focuses the active text input of the add form, if any
+func (m *sourcesModel) focusAddField() tea.Cmd {
+	var cmd tea.Cmd
+	for i := range m.addInputs {
+		if m.mode == sourcesModeAdd && i == m.addField {
+			cmd = m.addInputs[i].Focus()
+		} else {
+			m.addInputs[i].Blur()
+		}
+	}
+	return cmd
+}
+
+// submitAdd validates the add form and adds the source in the background
+func (m sourcesModel) submitAdd() (tea.Model, tea.Cmd) {
+	url := strings.TrimSpace(m.addInputs[addFieldURL].Value())
+	name := strings.TrimSpace(m.addInputs[addFieldName].Value())
+	sourceType := addSourceTypes[m.addType]
+
+	if url == "" {
+		m.addError = "A URL is required"
+		return m, nil
+	}
+	if name == "" && sourceType == sources.SourceTypeGit {
+		name = sources.DeriveNameFromURL(url)
+	}
+	if name == "" {
+		m.addError = "A name is required for this source type"
+		return m, nil
+	}
+
+	m.mode = sourcesModeList
+	m.focusAddField()
+	m.state.busy[name] = "adding"
+
+	// Show the new source straight away so its progress is visible
+	items := append(m.list.Items(), sourceItem{source: sources.Source{Name: name, URL: url, Type: sourceType}})
+	cmd := m.list.SetItems(items)
+	m.list.Select(len(items) - 1)
+	return m, tea.Batch(
+		cmd,
+		runSourceAction(name, "add", func() error {
+			return settings.AddSource(url, name, string(sourceType))
+		}),
+	)
+}
+
+func (m sourcesModel) View() string {
+	switch m.mode {
+	case sourcesModeRename:
+		return appStyle.Render(formStyle.Render(
+			formLabelStyle.Render("Rename "+m.target) + "\n\n" +
+				m.rename.View() + "\n\n" +
+				formHintStyle.Render("enter: save • esc: cancel")))
+
+	case sourcesModeDelete:
+		return appStyle.Render(formStyle.Render(
+			formLabelStyle.Render("Delete "+m.target+"?") + "\n\n" +
+				"This removes the source and its cached files.\n\n" +
+				formHintStyle.Render("y: delete • n: cancel")))
+
+	case sourcesModeAdd:
+		return appStyle.Render(m.addView())
+	}
+
+	return appStyle.Render(m.list.View())
+}
+
+// addView renders the add source form
+func (m sourcesModel) addView() string {
+	var b strings.Builder
+	b.WriteString(formLabelStyle.Render("Add a data source") + "\n\n")
+
+	b.WriteString(formLabelStyle.Render("URL") + "\n")
+	b.WriteString(m.addInputs[addFieldURL].View() + "\n\n")
+
+	b.WriteString(formLabelStyle.Render("Name") + "\n")
+	b.WriteString(m.addInputs[addFieldName].View() + "\n\n")
+
+	b.WriteString(formLabelStyle.Render("Type") + "\n")
+	options := make([]string, len(addSourceTypes))
+	for i, t := range addSourceTypes {
+		if i == m.addType {
+			options[i] = formSelectedStyle.Render(string(t))
+		} else {
+			options[i] = formOptionStyle.Render(string(t))
+		}
+	}
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, options...))
+	if m.addField == addFieldType {
+		b.WriteString(formHintStyle.Render("  ←/→ to choose"))
+	}
+	b.WriteString("\n\n")
+
+	if m.addError != "" {
+		b.WriteString(statusMessageStyle(m.addError) + "\n\n")
+	}
+	b.WriteString(formHintStyle.Render("tab: next field • enter: next/add • esc: cancel"))
+
+	return formStyle.Render(b.String())
+}
+
+// progressive turns an action such as "update" into "updating"
+func progressive(action string) string {
+	return strings.TrimSuffix(action, "e") + "ing"
+}
+
+// sourceDelegate renders sources like the default delegate, with the
+// progress or failure of operations in their description
+type sourceDelegate struct {
+	list.DefaultDelegate
+	state *sourcesState
+}
+
+func (d sourceDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if source, ok := item.(sourceItem); ok {
+		if action, busy := d.state.busy[source.source.Name]; busy {
+			source.status = d.state.spinner.View() + " " + action + "..."
+		} else if failure, failed := d.state.errors[source.source.Name]; failed {
+			source.status = "⚠️ " + failure
+		}
+		item = source
+	}
+	d.DefaultDelegate.Render(w, m, index, item)
+}
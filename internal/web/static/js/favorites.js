@@ -53,29 +53,64 @@ export function updateFavoriteButtons() {
     });
 }
 
-export function filterFavorites(query) {
+// filterFavorites applies the source and category filters
+export function filterFavorites(favorites) {
     const selectedSource = document.getElementById('favoriteSourceFilter').value;
     const selectedCategory = document.getElementById('favoriteCategoryFilter').value;
-    query = query ? query.toLowerCase() : '';
-    
-    return allFavorites.filter(f => {
-        const matchesQuery = !query || 
-            f.description.toLowerCase().includes(query) ||
-            f.category.toLowerCase().includes(query) ||
-            f.link.toLowerCase().includes(query);
-            
+
+    return favorites.filter(f => {
         const matchesSource = !selectedSource || f.source === selectedSource;
         const matchesCategory = !selectedCategory || f.category === selectedCategory;
-        
-        return matchesQuery && matchesSource && matchesCategory;
+
+        return matchesSource && matchesCategory;
     });
 }
 
+// Aborts the favorites search still in flight when the query changes
+let favoritesSearchController = null;
+
 export function updateFavoritesDisplay() {
     const query = document.getElementById('favoritesSearchInput').value.trim();
-    const filteredFavorites = filterFavorites(query);
+
+    if (favoritesSearchController) {
+        favoritesSearchController.abort();
+        favoritesSearchController = null;
+    }
+
+    if (!query) {
+        renderFavorites(filterFavorites(allFavorites));
+        return;
+    }
+
+    // Rank favorites with the same search engine as the search tab
+    favoritesSearchController = new AbortController();
+    const params = new URLSearchParams({ q: query, per_page: Math.max(allFavorites.length, 1) });
+    fetch(`/favorites/search?${params}`, { signal: favoritesSearchController.signal })
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to search favorites');
+            }
+            return response.json();
+        })
+        .then(data => {
+            const byLink = new Map(allFavorites.map(f => [f.link, f]));
+            const ranked = data.results
+                .filter(r => byLink.has(r.url))
+                .map(r => ({ ...byLink.get(r.url), score: r.score }));
+            renderFavorites(filterFavorites(ranked));
+        })
+        .catch(error => {
+            if (error.name === 'AbortError') {
+                return;
+            }
+            console.error('Error:', error);
+            showToast('Failed to search favorites', true);
+        });
+}
+
+function renderFavorites(filteredFavorites) {
     const favoritesDiv = document.getElementById('favorites');
-    
+
     if (filteredFavorites.length === 0) {
         favoritesDiv.innerHTML = '<div class="no-results">No favorites found</div>';
         return;
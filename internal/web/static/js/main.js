@@ -23,7 +23,6 @@ import {
 import {
   validateSearchInput,
   performSearch,
-  updateCategoryFilter,
 } from "./search.js";
 import { showToast } from "./ui.js";
 import { loadLibrary } from "./library.js";
@@ -92,13 +91,6 @@ document.addEventListener("DOMContentLoaded", function () {
       .getElementById("themeToggle")
       .addEventListener("click", toggleTheme);
 
-    // Add event listener for source filter changes
-    document
-      .getElementById("sourceFilter")
-      .addEventListener("change", function () {
-        performSearch(1);
-      });
-
     document
       .getElementById("favoriteSourceFilter")
       .addEventListener("change", function () {
@@ -239,13 +231,6 @@ document.addEventListener("DOMContentLoaded", function () {
         });
       });
 
-    // Add event listener for category filter changes
-    document
-      .getElementById("categoryFilter")
-      .addEventListener("change", function () {
-        performSearch(1);
-      });
-
     document
       .getElementById("favoriteCategoryFilter")
       .addEventListener("change", function () {
@@ -10,7 +10,10 @@ let totalPages = 1;
 let totalResults = 0;
 let currentQuery = "";
 let currentResults = [];
-let allSearchCategories = new Set();
+let selectedFacets = {};
+let openFacets = new Set();
+let searchController = null;
+let searchStream = null;
 
 // Validate search input
 export function validateSearchInput(input) {
@@ -56,34 +59,125 @@ export function validateSearchInput(input) {
   return true;
 }
 
-// Update category filter
-export function updateCategoryFilter(results, selectedCategory = "") {
-  const categoryFilter = document.getElementById("categoryFilter");
-
-  // Only update the full category list if this is a new search (not a filter)
-  if (!selectedCategory) {
-    allSearchCategories.clear();
-    // Add only valid categories from results
-    results.forEach((result) => {
-      // Skip invalid categories (longer than 80 characters)
-      if (result.category && result.category.length <= 80) {
-        allSearchCategories.add(result.category || "n/a");
-      }
+// Facet names in display order, with their headings
+const facetLabels = {
+  source: "Data sources",
+  source_type: "Source types",
+  category: "Categories",
+  tag: "Tags",
+  collection: "Collections",
+  domain: "Domains",
+};
+
+// Number of values shown per facet
+const maxFacetValues = 20;
+
+// Escape text for use in HTML content and attributes
+function escapeHTML(text) {
+  return String(text)
+    .replace(/&/g, "&amp;")
+    .replace(/</g, "&lt;")
+    .replace(/>/g, "&gt;")
+    .replace(/"/g, "&quot;");
+}
+
+// Append the selected facet values to the search parameters
+function appendFacetParams(params) {
+  Object.entries(selectedFacets).forEach(([facet, values]) => {
+    values.forEach((value) => {
+      params += `&${facet}=${encodeURIComponent(value)}`;
     });
+  });
+  return params;
+}
+
+// Clear every selected facet value and search again
+export function clearFacets() {
+  selectedFacets = {};
+  performSearch(1);
+}
+
+// Render the facet counts of a search response as multi-select filters
+export function updateFacets(facets) {
+  const facetPanel = document.getElementById("facetPanel");
+  if (!facets) {
+    facetPanel.innerHTML = "";
+    return;
   }
 
-  const options = ['<option value="">All categories</option>'];
-  Array.from(allSearchCategories)
-    .sort()
-    .forEach((category) => {
-      // Keep the n/a value instead of converting it to empty string
-      const selected = category === selectedCategory;
-      options.push(
-        `<option value="${category}"${selected ? " selected" : ""}>${category}</option>`,
-      );
+  const groups = Object.keys(facetLabels)
+    .map((facet) => {
+      const selected = selectedFacets[facet] || new Set();
+      const counts = (facets[facet] || []).slice(0, maxFacetValues);
+
+      // Keep selected values visible even when nothing matches them
+      selected.forEach((value) => {
+        if (!counts.some((count) => count.value === value)) {
+          counts.push({ value, count: 0 });
+        }
+      });
+      if (counts.length === 0) {
+        return "";
+      }
+
+      const options = counts
+        .map(
+          ({ value, count }) => `<label class="facet-option">
+              <input type="checkbox" data-facet="${facet}" data-value="${escapeHTML(value)}"${selected.has(value) ? " checked" : ""} />
+              <span class="facet-value">${escapeHTML(value)}</span>
+              <span class="facet-count">${count}</span>
+            </label>`,
+        )
+        .join("");
+      const summary =
+        selected.size > 0
+          ? `${facetLabels[facet]} (${selected.size})`
+          : facetLabels[facet];
+
+      return `<details class="facet-group" data-facet="${facet}"${openFacets.has(facet) ? " open" : ""}>
+            <summary>${summary}</summary>
+            <div class="facet-options">${options}</div>
+          </details>`;
+    })
+    .join("");
+
+  const hasSelection = Object.values(selectedFacets).some(
+    (values) => values.size > 0,
+  );
+  facetPanel.innerHTML =
+    groups +
+    (hasSelection
+      ? '<button class="facet-clear" id="clearFacets">Clear filters</button>'
+      : "");
+
+  facetPanel.querySelectorAll(".facet-group").forEach((group) => {
+    group.addEventListener("toggle", () => {
+      if (group.open) {
+        openFacets.add(group.dataset.facet);
+      } else {
+        openFacets.delete(group.dataset.facet);
+      }
     });
+  });
 
-  categoryFilter.innerHTML = options.join("");
+  facetPanel.querySelectorAll("input[data-facet]").forEach((checkbox) => {
+    checkbox.addEventListener("change", () => {
+      const facet = checkbox.dataset.facet;
+      const values = selectedFacets[facet] || new Set();
+      if (checkbox.checked) {
+        values.add(checkbox.dataset.value);
+      } else {
+        values.delete(checkbox.dataset.value);
+      }
+      selectedFacets[facet] = values;
+      performSearch(1);
+    });
+  });
+
+  const clearButton = document.getElementById("clearFacets");
+  if (clearButton) {
+    clearButton.addEventListener("click", clearFacets);
+  }
 }
 
 // Update pagination
@@ -184,7 +278,6 @@ function createResultHTML(result, showScore = true) {
   const isFavorite = document
     .querySelector(`.favorite-btn[data-link="${result.url}"]`)
     ?.classList.contains("active");
-  const sourceColor = getSourceColor(result.source, new Map());
   const currentSettings = getCurrentSettings();
 
   // Check if category is invalid
@@ -232,6 +325,36 @@ function createResultHTML(result, showScore = true) {
     scoreHtml = `<div class="score-indicator">${bars.join("")}</div>`;
   }
 
+  // Show every list a merged result was found in
+  const sources = [
+    ...new Set((result.appearances || []).map((a) => a.source)),
+  ];
+  if (sources.length === 0) {
+    sources.push(result.source);
+  }
+  const sourceTags = sources
+    .map(
+      (source) =>
+        `<div class="source-tag" style="background-color: ${getSourceColor(source, new Map())}">${source}</div>`,
+    )
+    .join("");
+
+  let appearsHtml = "";
+  if (result.appears_in > 1) {
+    const provenance = result.appearances
+      .map((a) => `${a.source}: ${a.category || "n/a"}`)
+      .join("\n")
+      .replace(/"/g, "&quot;");
+    appearsHtml = `<div class="appears-tag" title="${provenance}">in ${result.appears_in} lists</div>`;
+  }
+
+  // Link to the line the result was found on, for git sources on known hosts
+  let viewSourceHtml = "";
+  if (result.source_url) {
+    const location = `${result.file_path}${result.line_number ? `:${result.line_number}` : ""}`;
+    viewSourceHtml = `<a class="view-source-link" href="${result.source_url}" target="_blank" rel="noopener" title="${location.replace(/"/g, "&quot;")}">View in source</a>`;
+  }
+
   // Create tooltip container
   const tooltipId = `tooltip-${result.url.replace(/[^a-zA-Z0-9]/g, "-")}`;
   const tooltipHTML = `<div class="result-tooltip" id="${tooltipId}">${result.description}</div>`;
@@ -264,7 +387,8 @@ function createResultHTML(result, showScore = true) {
                             ? `<div class="warning-tag">⚠️ Invalid category</div>`
                             : `<div class="category-tag">${result.category || "n/a"}</div>`
                         }
-                        <div class="source-tag" style="background-color: ${sourceColor}">${result.source}</div>
+                        ${sourceTags}
+                        ${appearsHtml}
                         <span class="result-domain">${getDisplayText(result.url)}</span>
                         <button class="favorite-btn ${isFavorite ? "active" : ""}"
                                 data-link="${result.url}"
@@ -282,6 +406,7 @@ function createResultHTML(result, showScore = true) {
                             <div class="kebab-menu-content">
                                 <button class="add-source-btn" data-url="${result.url}">Add data source</button>
                                 <button class="scan-virustotal-btn" data-url="${result.url}">Scan with VirusTotal</button>
+                                ${viewSourceHtml}
                             </div>
                         </div>
                     </div>
@@ -324,6 +449,98 @@ function addDescriptionTooltipListeners() {
   });
 }
 
+// Render the result cards and attach their listeners
+function renderResultList(results) {
+  const resultsDiv = document.getElementById("results");
+  resultsDiv.innerHTML = results
+    .map((result) => createResultHTML(result, true))
+    .join("");
+
+  // Add event listeners for description toggles
+  addDescriptionToggleListeners();
+
+  // Add event listeners for description tooltips
+  addDescriptionTooltipListeners();
+
+  // Add kebab menu listeners
+  addKebabMenuListeners();
+}
+
+// Render a paginated search response from /search or the final stream event
+function renderSearchResponse(data) {
+  const resultsDiv = document.getElementById("results");
+
+  // Facets are shown even without results so filters can be deselected
+  updateFacets(data && data.facets);
+
+  if (!data || !data.results || data.results.length === 0) {
+    resultsDiv.innerHTML =
+      '<div class="no-results">No results found. Go to Settings to update your sources.</div>';
+    return;
+  }
+
+  // Store current results
+  currentResults = data.results;
+
+  // Display results
+  renderResultList(currentResults);
+
+  // Update pagination info
+  totalPages = data.total_pages;
+  totalResults = data.total_results;
+  updatePagination();
+}
+
+// Cancel whichever search request is still in flight
+function cancelPendingSearch() {
+  // Aborting makes the server stop working on the stale query
+  if (searchController) {
+    searchController.abort();
+    searchController = null;
+  }
+  if (searchStream) {
+    searchStream.close();
+    searchStream = null;
+  }
+}
+
+// Stream the first page of results, showing each source's best matches as
+// soon as they arrive and replacing them with the final ranking at the end
+function streamSearch(params) {
+  const resultsDiv = document.getElementById("results");
+  const stream = new EventSource(`/search/stream?${params}`);
+  searchStream = stream;
+  let provisional = [];
+
+  stream.addEventListener("results", (event) => {
+    const batch = JSON.parse(event.data);
+    provisional = provisional
+      .concat(batch.results)
+      .sort((a, b) => b.score - a.score);
+    renderResultList(provisional);
+    resultsDiv.insertAdjacentHTML(
+      "beforeend",
+      '<div class="loading">Searching remaining sources...</div>',
+    );
+  });
+
+  stream.addEventListener("done", (event) => {
+    stream.close();
+    searchStream = null;
+    renderSearchResponse(JSON.parse(event.data));
+  });
+
+  stream.addEventListener("error", (event) => {
+    stream.close();
+    searchStream = null;
+    // Server-sent error events carry a message; connection errors do not
+    const message = event.data
+      ? JSON.parse(event.data).error
+      : "Failed to fetch results. Please try again.";
+    resultsDiv.innerHTML = `<div class="error">${message}</div>`;
+  });
+}
+
 // Perform search
 export function performSearch(page = 1) {
   const query = document.getElementById("searchInput").value.trim();
@@ -331,11 +548,8 @@ export function performSearch(page = 1) {
     return;
   }
 
-  const resultsPerPage = getCurrentSettings()
-    ? getCurrentSettings().resultsPerPage
-    : 10;
-  const selectedSource = document.getElementById("sourceFilter").value;
-  const selectedCategory = document.getElementById("categoryFilter").value;
+  const settings = getCurrentSettings();
+  const resultsPerPage = settings ? settings.resultsPerPage : 10;
   currentQuery = query;
   currentPage = page;
 
@@ -343,56 +557,44 @@ export function performSearch(page = 1) {
   resultsDiv.innerHTML = '<div class="loading">Searching...</div>';
   document.getElementById("pagination").innerHTML = "";
 
-  let url = `/search?q=${encodeURIComponent(query)}&page=${page}&per_page=${resultsPerPage}`;
-  if (selectedSource) {
-    url += `&source=${encodeURIComponent(selectedSource)}`;
-  }
-  if (selectedCategory) {
-    url += `&category=${encodeURIComponent(selectedCategory)}`;
+  const params = appendFacetParams(
+    `q=${encodeURIComponent(query)}&page=${page}&per_page=${resultsPerPage}`,
+  );
+
+  cancelPendingSearch();
+
+  // Stream new live searches so the first results show up early
+  if (
+    page === 1 &&
+    window.EventSource &&
+    !(settings && settings.usePreprocessedSearch)
+  ) {
+    streamSearch(params);
+    return;
   }
 
-  fetch(url)
+  searchController = new AbortController();
+
+  fetch(`/search?${params}`, { signal: searchController.signal })
     .then((response) => {
+      if (response.status === 504) {
+        throw new Error("Search timed out");
+      }
       if (!response.ok) {
         throw new Error("Failed to fetch results");
       }
       return response.json();
     })
-    .then((data) => {
-      if (!data || !data.results || data.results.length === 0) {
-        resultsDiv.innerHTML =
-          '<div class="no-results">No results found. Go to Settings to update your sources.</div>';
+    .then((data) => renderSearchResponse(data))
+    .catch((error) => {
+      // A newer search superseded this one; leave the UI to it
+      if (error.name === "AbortError") {
         return;
       }
-
-      // Store current results
-      currentResults = data.results;
-
-      // Only update category filter with full list on new searches, not category filters
-      updateCategoryFilter(data.results, selectedCategory);
-
-      // Display results
-      resultsDiv.innerHTML = currentResults
-        .map((result) => createResultHTML(result, true))
-        .join("");
-
-      // Add event listeners for description toggles
-      addDescriptionToggleListeners();
-
-      // Add event listeners for description tooltips
-      addDescriptionTooltipListeners();
-
-      // Add kebab menu listeners
-      addKebabMenuListeners();
-
-      // Update pagination info
-      totalPages = data.total_pages;
-      totalResults = data.total_results;
-      updatePagination();
-    })
-    .catch((error) => {
       console.error("Error:", error);
       resultsDiv.innerHTML =
-        '<div class="error">Failed to fetch results. Please try again.</div>';
+        error.message === "Search timed out"
+          ? '<div class="error">Search timed out. Try a more specific query.</div>'
+          : '<div class="error">Failed to fetch results. Please try again.</div>';
     });
 }
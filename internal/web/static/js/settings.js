@@ -27,6 +27,7 @@ export function loadSettings() {
         customHeader: "find cool stuff",
         minFuzzyScore: 0,
         searchConcurrency: 1,
+        searchTimeout: 10000,
       };
 
       // Merge settings with defaults
@@ -49,6 +50,7 @@ export function loadSettings() {
       document.getElementById("minFuzzyScore").value = settings.minFuzzyScore;
       document.getElementById("searchConcurrency").value =
         settings.searchConcurrency;
+      document.getElementById("searchTimeout").value = settings.searchTimeout;
       updateHeaderText(settings.customHeader);
       currentSettings = settings;
       return settings;
@@ -86,6 +88,10 @@ export function saveSettings() {
     searchConcurrency: parseInt(
       document.getElementById("searchConcurrency").value,
     ),
+    searchTimeout: parseInt(document.getElementById("searchTimeout").value),
+    checkLinks: existingSettings.checkLinks || false,
+    deadLinks: existingSettings.deadLinks || "demote",
+    categoryOverrides: existingSettings.categoryOverrides || {},
     sources: existingSettings.sources || [], // Preserve the sources array
   };
 
@@ -132,6 +138,7 @@ export function resetSettings() {
       document.getElementById("minFuzzyScore").value = settings.minFuzzyScore;
       document.getElementById("searchConcurrency").value =
         settings.searchConcurrency;
+      document.getElementById("searchTimeout").value = settings.searchTimeout;
       updateHeaderText(settings.customHeader);
 
       // Save current settings
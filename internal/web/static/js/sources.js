@@ -1,6 +1,6 @@
 import { showToast } from "./ui.js";
 
-// Load sources into the filter dropdown
+// Load sources into the favorites filter dropdown
 export function loadSourceFilter() {
   fetch("/sources/list")
     .then((response) => {
@@ -14,7 +14,6 @@ export function loadSourceFilter() {
         throw new Error(data.error || "Failed to load sources");
       }
 
-      const searchFilter = document.getElementById("sourceFilter");
       const favoritesFilter = document.getElementById("favoriteSourceFilter");
       const options =
         '<option value="">All data sources</option>' +
@@ -26,14 +25,12 @@ export function loadSourceFilter() {
           )
           .join("");
 
-      searchFilter.innerHTML = options;
       favoritesFilter.innerHTML = options;
     })
     .catch((error) => {
       console.error("Error:", error);
       const errorOption =
         '<option value="">Error loading data sources</option>';
-      document.getElementById("sourceFilter").innerHTML = errorOption;
       document.getElementById("favoriteSourceFilter").innerHTML = errorOption;
     });
 }
@@ -484,30 +481,113 @@ export function processSource() {
   processButton.disabled = true;
   processButton.textContent = "Processing sources...";
 
+  const reset = () => {
+    processButton.disabled = false;
+    processButton.textContent = "Process sources";
+  };
+
   fetch("/process", {
     method: "POST",
   })
     .then((response) => {
+      // A run started elsewhere is followed like our own
+      if (response.status === 409) {
+        return { success: true, sources: 1 };
+      }
       if (!response.ok) {
         throw new Error("Failed to process sources");
       }
       return response.json();
     })
     .then((data) => {
-      if (data.success) {
-        showToast(`${data.message} (${data.duration})`);
+      if (!data.sources) {
+        showToast(data.message);
+        reset();
+        return;
       }
+      followProcessing(processButton, reset);
     })
     .catch((error) => {
       console.error("Error:", error);
-      showToast("Failed to process sources", "error");
-    })
-    .finally(() => {
-      processButton.disabled = false;
-      processButton.textContent = "Process sources";
+      showToast("Failed to process sources", true);
+      reset();
     });
 }
 
+// Show the progress of processing sources on the button until it finishes
+function followProcessing(processButton, reset) {
+  const stream = new EventSource("/process/status/stream");
+
+  stream.addEventListener("status", (event) => {
+    const progress = JSON.parse(event.data);
+    const percent = Math.floor(processingFraction(progress) * 100);
+    const current = progress.sources.find((s) => s.status === "processing");
+    processButton.textContent = current
+      ? `Processing ${percent}% (${current.source_name}: ${current.stage})`
+      : `Processing ${percent}%`;
+  });
+
+  stream.addEventListener("done", (event) => {
+    stream.close();
+    const progress = JSON.parse(event.data);
+    const failed = progress.sources.filter((s) => s.status === "error");
+    const processed = progress.sources.length - failed.length;
+    const duration = formatDuration(progress.started_at, progress.completed_at);
+    if (failed.length > 0) {
+      showToast(
+        `Processing completed with some errors. ${processed} sources processed, ${failed.length} failed.`,
+        true,
+      );
+    } else {
+      showToast(`All ${processed} sources processed successfully${duration}`);
+    }
+    reset();
+  });
+
+  stream.addEventListener("error", (event) => {
+    stream.close();
+    // Server-sent error events carry a message; connection errors do not
+    const message = event.data
+      ? JSON.parse(event.data).error
+      : "Lost track of processing sources";
+    showToast(message, true);
+    reset();
+  });
+}
+
+// How much of a processing run is done, from 0 to 1, matching the CLI's bar
+function processingFraction(progress) {
+  if (progress.sources.length === 0) {
+    return 0;
+  }
+  const done = progress.sources.reduce((sum, s) => {
+    if (s.status !== "pending" && s.status !== "processing") {
+      return sum + 1;
+    }
+    if (!s.files_total) {
+      return sum;
+    }
+    let fraction = (s.files_processed || 0) / s.files_total;
+    if (s.check_links) {
+      fraction /= 2;
+      if (s.links_total) {
+        fraction += (0.5 * (s.links_checked || 0)) / s.links_total;
+      }
+    }
+    return sum + Math.min(fraction, 0.99);
+  }, 0);
+  return done / progress.sources.length;
+}
+
+// Format how long a run took as " (1.2s)", or nothing without both times
+function formatDuration(startedAt, completedAt) {
+  if (!startedAt || !completedAt) {
+    return "";
+  }
+  const seconds = (new Date(completedAt) - new Date(startedAt)) / 1000;
+  return ` (${seconds.toFixed(1)}s)`;
+}
+
 // Helper function to format source type for display
 export function formatSourceType(type) {
   const typeMap = {
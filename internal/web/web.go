@@ -1,8 +1,11 @@
 package web
 
 import (
+	"bytes"
+	"context"
 	"embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -15,6 +18,7 @@ import (
 	"time"
 
 	"freectl/internal/common"
+	"freectl/internal/feeds"
 	"freectl/internal/preprocessing"
 	"freectl/internal/search"
 	"freectl/internal/settings"
@@ -89,6 +93,118 @@ func HandleStatic(w http.ResponseWriter, r *http.Request) {
 	w.Write(content)
 }
 
+// validateSearchQuery checks a raw query against the configured limits and
+// returns a user-facing message describing the first problem found
+func validateSearchQuery(query string, s settings.Settings) string {
+	if query == "" {
+		return "Missing search query"
+	}
+
+	// Validate query length
+	if len(query) > s.MaxQueryLength {
+		return "Search query too long"
+	}
+
+	// Validate minimum length
+	if len(strings.TrimSpace(query)) < s.MinQueryLength {
+		return "Search query too short"
+	}
+
+	// Validate for potentially dangerous characters
+	if strings.ContainsAny(query, "<>") {
+		return "Invalid characters in search query"
+	}
+
+	return ""
+}
+
+// searchResponse is the paginated payload returned by the search endpoints
+type searchResponse struct {
+	Results      []SearchResult                 `json:"results"`
+	TotalResults int                            `json:"total_results"`
+	TotalPages   int                            `json:"total_pages"`
+	CurrentPage  int                            `json:"current_page"`
+	PerPage      int                            `json:"per_page"`
+	Facets       map[string][]search.FacetCount `json:"facets"`
+}
+
+// parseSearchRequest builds a search request from the URL query parameters
+func parseSearchRequest(r *http.Request) search.Request {
+	params := r.URL.Query()
+
+	// Get pagination parameters
+	page, _ := strconv.Atoi(params.Get("page"))
+	perPage, _ := strconv.Atoi(params.Get("per_page"))
+
+	req := search.Request{
+		Query:   params.Get("q"),
+		Page:    page,
+		PerPage: perPage,
+		Sort:    params.Get("sort"),
+	}
+	req.DeadLinks = params.Get("dead_links")
+
+	// Filters may be repeated to select several values, e.g. source=a&source=b
+	req.Filters = search.SearchFilters{
+		Sources:     params["source"],
+		Categories:  params["category"],
+		Tags:        params["tag"],
+		Collections: params["collection"],
+		Domains:     params["domain"],
+		SourceTypes: params["source_type"],
+	}
+	if facets := params.Get("facets"); facets != "" {
+		req.Facets = strings.Split(facets, ",")
+	}
+
+	return req
+}
+
+// toSearchResults converts results for JSON encoding, rendering their
+// markdown descriptions to HTML
+func toSearchResults(results []search.Result) []SearchResult {
+	converted := make([]SearchResult, 0, len(results))
+	for _, r := range results {
+		appearances := make([]search.Appearance, 0, len(r.Appearances))
+		for _, a := range r.Appearances {
+			a.Description = common.RenderMarkdown(a.Description)
+			appearances = append(appearances, a)
+		}
+
+		converted = append(converted, SearchResult{
+			Category:    r.Category,
+			Description: common.RenderMarkdown(r.Description),
+			URL:         r.URL,
+			Name:        r.Name,
+			Score:       r.Score,
+			Source:      r.Source,
+			SourceType:  r.SourceType,
+			Tags:        r.Tags,
+			Collections: r.Collections,
+			Appearances: appearances,
+			AppearsIn:   r.AppearsIn,
+			FilePath:    r.FilePath,
+			LineNumber:  r.LineNumber,
+			SourceURL:   r.SourceURL,
+			LinkStatus:  r.LinkStatus,
+			Dead:        r.Dead,
+		})
+	}
+	return converted
+}
+
+// toSearchResponse converts a searcher response for JSON encoding
+func toSearchResponse(resp *search.Response) searchResponse {
+	return searchResponse{
+		Results:      toSearchResults(resp.Results),
+		TotalResults: resp.TotalResults,
+		TotalPages:   resp.TotalPages,
+		CurrentPage:  resp.CurrentPage,
+		PerPage:      resp.PerPage,
+		Facets:       resp.Facets,
+	}
+}
+
 func HandleSearch(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("q")
 	if query == "" {
@@ -104,112 +220,328 @@ func HandleSearch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate query length
-	if len(query) > settings.MaxQueryLength {
-		http.Error(w, "Search query too long", http.StatusBadRequest)
+	if msg := validateSearchQuery(query, settings); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
 		return
 	}
 
-	// Validate minimum length
-	if len(strings.TrimSpace(query)) < settings.MinQueryLength {
-		http.Error(w, "Search query too short", http.StatusBadRequest)
+	req := parseSearchRequest(r)
+	searcher := search.NewSearcher(settings)
+
+	// The request context is cancelled when the client disconnects, e.g. when
+	// the browser aborts a stale request because the user kept typing
+	log.Info("Starting search", "query", query, "filters", req.Filters, "searcher", searcher.Name())
+	resp, err := searcher.Search(r.Context(), req)
+	if errors.Is(err, context.Canceled) {
+		log.Debug("Search cancelled by client", "query", query)
+		return
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		log.Warn("Search timed out", "query", query, "timeout_ms", settings.SearchTimeout)
+		http.Error(w, "Search timed out", http.StatusGatewayTimeout)
+		return
+	}
+	if err != nil {
+		log.Error("Search failed", "error", err)
+		http.Error(w, fmt.Sprintf("Search failed: %s", err.Error()), http.StatusInternalServerError)
 		return
 	}
 
-	// Validate for potentially dangerous characters
-	if strings.ContainsAny(query, "<>") {
-		http.Error(w, "Invalid characters in search query", http.StatusBadRequest)
+	log.Info("Search completed", "results", resp.TotalResults)
+	if req.Page <= 1 {
+		recordSearch(req, resp.TotalResults)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toSearchResponse(resp))
+}
+
+// HandleSearchStream streams search results as Server-Sent Events. A
+// "results" event is sent for each source as soon as it has been searched,
+// followed by a single "done" event carrying the first page of the final,
+// re-ranked results in the same shape as /search. Failures are reported as an
+// "error" event. Searchers that cannot stream, such as preprocessed search,
+// only send "done".
+func HandleSearchStream(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+
+	settings, err := settings.LoadSettings()
+	if err != nil {
+		log.Error("Failed to load settings", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	// Get pagination parameters
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page < 1 {
-		page = 1
+	if msg := validateSearchQuery(query, settings); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
 	}
 
-	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
-	if perPage < 1 {
-		perPage = 10
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
 	}
 
-	// Get source and category filters from query parameters
-	sourceName := r.URL.Query().Get("source")
-	category := r.URL.Query().Get("category")
-
-	// Perform search based on settings
-	log.Info("Starting search", "query", query, "source", sourceName, "preprocessed", settings.UsePreprocessedSearch)
-	log.Debug("Settings debug", "usePreprocessedSearch", settings.UsePreprocessedSearch, "resultsPerPage", settings.ResultsPerPage)
-	var results []search.Result
-	if settings.UsePreprocessedSearch {
-		log.Info("Using preprocessed search")
-		results, err = search.SearchPreprocessed(query, sourceName, settings, 0)
+	// The stream always delivers the first page
+	req := parseSearchRequest(r)
+	req.Page = 1
+	searcher := search.NewSearcher(settings)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	log.Info("Starting streamed search", "query", query, "filters", req.Filters, "searcher", searcher.Name())
+
+	var resp *search.Response
+	if streamer, ok := searcher.(search.StreamingSearcher); ok {
+		batches := make(chan search.SourceBatch)
+		searchDone := make(chan struct{})
+		go func() {
+			defer close(searchDone)
+			resp, err = streamer.SearchStream(r.Context(), req, batches)
+		}()
+
+	stream:
+		for {
+			select {
+			case batch := <-batches:
+				writeEvent(w, flusher, "results", map[string]interface{}{
+					"source":  batch.Source,
+					"results": toSearchResults(previewBatch(batch.Results, req)),
+				})
+			case <-searchDone:
+				break stream
+			}
+		}
 	} else {
-		log.Info("Using real-time search")
-		results, err = search.Search(query, sourceName, settings)
+		resp, err = searcher.Search(r.Context(), req)
+	}
+
+	if errors.Is(err, context.Canceled) {
+		log.Debug("Streamed search cancelled by client", "query", query)
+		return
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		log.Warn("Streamed search timed out", "query", query, "timeout_ms", settings.SearchTimeout)
+		writeEvent(w, flusher, "error", map[string]string{"error": "Search timed out"})
+		return
 	}
 	if err != nil {
-		log.Error("Search failed", "error", err)
-		http.Error(w, fmt.Sprintf("Search failed: %s", err.Error()), http.StatusInternalServerError)
+		log.Error("Streamed search failed", "error", err)
+		writeEvent(w, flusher, "error", map[string]string{"error": fmt.Sprintf("Search failed: %s", err.Error())})
+		return
+	}
+
+	log.Info("Streamed search completed", "results", resp.TotalResults)
+	recordSearch(req, resp.TotalResults)
+	writeEvent(w, flusher, "done", toSearchResponse(resp))
+}
+
+// recordSearch adds a search to the history, only logging failures since the
+// search itself succeeded
+func recordSearch(req search.Request, total int) {
+	if err := search.RecordSearch(req.Query, req.Filters, total); err != nil {
+		log.Warn("Failed to record search history", "error", err)
+	}
+}
+
+// HandleHistory returns recent searches, most recent first, limited by the
+// optional limit parameter. DELETE clears the history.
+func HandleHistory(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		history, err := search.LoadHistory()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if limit, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && limit > 0 && len(history) > limit {
+			history = history[:limit]
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(history)
+
+	case http.MethodDelete:
+		if err := search.ClearHistory(); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// savedSearchSummary is a saved search without the URLs it has found
+type savedSearchSummary struct {
+	Name      string               `json:"name"`
+	Query     string               `json:"query"`
+	Filters   search.SearchFilters `json:"filters"`
+	CreatedAt time.Time            `json:"created_at"`
+	LastRunAt time.Time            `json:"last_run_at,omitzero"`
+	Found     int                  `json:"found"`
+}
+
+// HandleSavedSearches lists saved searches. POST saves one from a JSON body
+// with name, query and optional filters.
+func HandleSavedSearches(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var req struct {
+			Name    string               `json:"name"`
+			Query   string               `json:"query"`
+			Filters search.SearchFilters `json:"filters"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := search.SaveSearch(req.Name, req.Query, req.Filters); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	saved, err := search.LoadSavedSearches()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	summaries := make([]savedSearchSummary, len(saved))
+	for i, s := range saved {
+		summaries[i] = savedSearchSummary{
+			Name:      s.Name,
+			Query:     s.Query,
+			Filters:   s.Filters,
+			CreatedAt: s.CreatedAt,
+			LastRunAt: s.LastRunAt,
+			Found:     len(s.Seen),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summaries)
+}
+
+// HandleSavedSearchFeed serves the results a saved search found after its
+// first run as a feed, at /feeds/saved/<name>.xml for Atom or
+// /feeds/saved/<name>.rss for RSS 2.0. Reading a feed doesn't run the
+// search, that happens after sources are updated or processed.
+func HandleSavedSearchFeed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	file := strings.TrimPrefix(r.URL.Path, "/feeds/saved/")
+	ext := filepath.Ext(file)
+	name := strings.TrimSuffix(file, ext)
+	if name == "" || (ext != ".xml" && ext != ".rss") {
+		http.NotFound(w, r)
+		return
+	}
+
+	saved, err := search.GetSavedSearch(name)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	base := scheme + "://" + r.Host
+	feed := feeds.Feed{
+		Title:       "freectl: " + saved.Name,
+		Description: fmt.Sprintf("New results for %q", saved.Query),
+		Link:        base + "/",
+		Self:        base + r.URL.Path,
+		Updated:     saved.LastRunAt,
+	}
+	for _, alert := range saved.Alerts {
+		feed.Entries = append(feed.Entries, feeds.Entry{
+			Title:    alert.Name,
+			Link:     alert.URL,
+			Summary:  alert.Description,
+			Category: alert.Category,
+			Author:   alert.Source,
+			Updated:  alert.FoundAt,
+		})
+	}
+
+	if ext == ".rss" {
+		w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+		err = feeds.WriteRSS(w, feed)
+	} else {
+		w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+		err = feeds.WriteAtom(w, feed)
+	}
+	if err != nil {
+		log.Error("Failed to write feed", "name", name, "error", err)
+	}
+}
+
+// refreshSavedSearches re-runs saved searches after an update or processing
+// so their feeds pick up what was added
+func refreshSavedSearches() {
+	s, err := settings.LoadSettings()
+	if err != nil {
+		log.Warn("Failed to load settings", "error", err)
 		return
 	}
+	found, err := search.RefreshSavedSearches(context.Background(), search.NewSearcher(s))
+	if err != nil {
+		log.Warn("Failed to refresh saved searches", "error", err)
+	}
+	if found > 0 {
+		log.Info("Saved searches found new results", "count", found)
+	}
+}
 
-	log.Info("Search completed", "results", len(results))
+// previewBatch picks the best filtered matches of a single source batch,
+// since only a page worth of them is useful before the final ranking
+func previewBatch(results []search.Result, req search.Request) []search.Result {
+	perPage := req.PerPage
+	if perPage < 1 {
+		perPage = 10
+	}
 
-	// Deduplicate results based on URL and filter by category if specified
 	seen := make(map[string]bool)
-	uniqueResults := make([]SearchResult, 0, len(results))
+	var preview []search.Result
 	for _, r := range results {
-		if !seen[r.URL] && (category == "" || r.Category == category) {
-			seen[r.URL] = true
-			uniqueResults = append(uniqueResults, SearchResult{
-				Category:    r.Category,
-				Description: common.RenderMarkdown(r.Description),
-				URL:         r.URL,
-				Name:        r.Name,
-				Score:       r.Score,
-				Source:      r.Source,
-			})
-		}
-	}
-
-	// Calculate pagination
-	totalResults := len(uniqueResults)
-	totalPages := 1 // Default to 1 page even with no results
-	if totalResults > 0 {
-		totalPages = (totalResults + perPage - 1) / perPage
-	}
-	if page > totalPages {
-		page = totalPages
-	}
-
-	// Handle pagination for empty results
-	var paginatedResults []SearchResult
-	if totalResults > 0 {
-		start := (page - 1) * perPage
-		end := start + perPage
-		if end > totalResults {
-			end = totalResults
-		}
-		paginatedResults = uniqueResults[start:end]
-	}
-
-	response := struct {
-		Results      []SearchResult `json:"results"`
-		TotalResults int            `json:"total_results"`
-		TotalPages   int            `json:"total_pages"`
-		CurrentPage  int            `json:"current_page"`
-		PerPage      int            `json:"per_page"`
-	}{
-		Results:      paginatedResults,
-		TotalResults: totalResults,
-		TotalPages:   totalPages,
-		CurrentPage:  page,
-		PerPage:      perPage,
+		if len(preview) == perPage {
+			break
+		}
+		if key := common.CanonicalURL(r.URL); !seen[key] && req.Filters.Matches(r) {
+			seen[key] = true
+			preview = append(preview, r)
+		}
 	}
+	return preview
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+// writeEvent writes a single Server-Sent Event with a JSON payload
+func writeEvent(w http.ResponseWriter, flusher http.Flusher, event string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		log.Error("Failed to encode event", "event", event, "error", err)
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	flusher.Flush()
 }
 
 func HandleFavorites(w http.ResponseWriter, r *http.Request) {
@@ -224,6 +556,15 @@ func HandleFavorites(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Optionally narrow down to a collection and/or tag
+	filter := search.FavoriteFilter{
+		Collection: r.URL.Query().Get("collection"),
+		Tag:        r.URL.Query().Get("tag"),
+	}
+	if filter.Collection != "" || filter.Tag != "" {
+		favorites = search.FilterFavorites(favorites, filter)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(favorites)
 }
@@ -296,6 +637,270 @@ func HandleRemoveFavorite(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(favorites)
 }
 
+// HandleUpdateFavorite edits a favorite. Fields left out of the request are
+// kept as they are.
+func HandleUpdateFavorite(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Link        string    `json:"link"`
+		Name        *string   `json:"name"`
+		Description *string   `json:"description"`
+		Category    *string   `json:"category"`
+		Notes       *string   `json:"notes"`
+		Tags        *[]string `json:"tags"`
+		Collections *[]string `json:"collections"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err := search.UpdateFavorite(req.Link, func(f *search.Favorite) {
+		if req.Name != nil {
+			f.Name = *req.Name
+		}
+		if req.Description != nil {
+			f.Description = *req.Description
+		}
+		if req.Category != nil {
+			f.Category = *req.Category
+		}
+		if req.Notes != nil {
+			f.Notes = *req.Notes
+		}
+		if req.Tags != nil {
+			f.Tags = *req.Tags
+		}
+		if req.Collections != nil {
+			f.Collections = *req.Collections
+		}
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	writeFavorites(w)
+}
+
+// HandleMoveFavorite moves a favorite to a new position in the list
+func HandleMoveFavorite(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Link     string `json:"link"`
+		Position int    `json:"position"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := search.MoveFavorite(req.Link, req.Position); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	writeFavorites(w)
+}
+
+// HandleSearchFavorites searches the favorites with the same matching,
+// filters and facets as /search, optionally within a collection
+func HandleSearchFavorites(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		http.Error(w, "Missing search query", http.StatusBadRequest)
+		return
+	}
+
+	settings, err := settings.LoadSettings()
+	if err != nil {
+		log.Error("Failed to load settings", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	filter := search.FavoriteFilter{Collection: r.URL.Query().Get("collection")}
+	resp, err := search.NewFavoritesSearcher(settings, filter).Search(r.Context(), parseSearchRequest(r))
+	if err != nil {
+		log.Error("Favorites search failed", "error", err)
+		http.Error(w, fmt.Sprintf("Search failed: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toSearchResponse(resp))
+}
+
+// HandleExportFavorites downloads the favorites as browser bookmarks,
+// markdown, JSON or an awesome list, optionally only those in a collection
+func HandleExportFavorites(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	params := r.URL.Query()
+	name := params.Get("format")
+	if name == "" {
+		name = string(search.FavoritesAwesome)
+	}
+	format, err := search.ParseFavoritesFormat(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	favorites, err := search.LoadFavorites()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	favorites = search.FilterFavorites(favorites, search.FavoriteFilter{
+		Collection: params.Get("collection"),
+		Tag:        params.Get("tag"),
+	})
+
+	var buf bytes.Buffer
+	if format == search.FavoritesAwesome {
+		err = search.ExportAwesomeList(&buf, favorites, search.AwesomeList{
+			Title:       params.Get("title"),
+			Description: params.Get("description"),
+			GroupBy:     params.Get("group_by"),
+		})
+	} else {
+		err = search.ExportFavorites(&buf, favorites, format)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filename, contentType := "favorites.md", "text/markdown; charset=utf-8"
+	switch format {
+	case search.FavoritesHTML:
+		filename, contentType = "bookmarks.html", "text/html; charset=utf-8"
+	case search.FavoritesJSON:
+		filename, contentType = "favorites.json", "application/json"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Write(buf.Bytes())
+}
+
+// writeFavorites responds with the updated list of favorites
+func writeFavorites(w http.ResponseWriter) {
+	favorites, err := search.LoadFavorites()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(favorites)
+}
+
+// HandleCollections lists the favorite collections with their sizes
+func HandleCollections(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeCollections(w)
+}
+
+// collectionRequest is the body of the collection management endpoints
+type collectionRequest struct {
+	Name        string `json:"name"`
+	NewName     string `json:"new_name"`
+	Description string `json:"description"`
+	Link        string `json:"link"`
+}
+
+// HandleCreateCollection creates an empty collection
+func HandleCreateCollection(w http.ResponseWriter, r *http.Request) {
+	handleCollectionChange(w, r, func(req collectionRequest) error {
+		return search.CreateCollection(req.Name, req.Description)
+	})
+}
+
+// HandleRenameCollection renames a collection
+func HandleRenameCollection(w http.ResponseWriter, r *http.Request) {
+	handleCollectionChange(w, r, func(req collectionRequest) error {
+		return search.RenameCollection(req.Name, req.NewName)
+	})
+}
+
+// HandleDeleteCollection deletes a collection, keeping its favorites
+func HandleDeleteCollection(w http.ResponseWriter, r *http.Request) {
+	handleCollectionChange(w, r, func(req collectionRequest) error {
+		return search.DeleteCollection(req.Name)
+	})
+}
+
+// HandleAddToCollection puts a favorite in a collection
+func HandleAddToCollection(w http.ResponseWriter, r *http.Request) {
+	handleCollectionChange(w, r, func(req collectionRequest) error {
+		return search.AddToCollection(req.Link, req.Name)
+	})
+}
+
+// HandleRemoveFromCollection takes a favorite out of a collection
+func HandleRemoveFromCollection(w http.ResponseWriter, r *http.Request) {
+	handleCollectionChange(w, r, func(req collectionRequest) error {
+		return search.RemoveFromCollection(req.Link, req.Name)
+	})
+}
+
+// handleCollectionChange decodes a collection request, applies change and
+// responds with the updated collections
+func handleCollectionChange(w http.ResponseWriter, r *http.Request, change func(collectionRequest) error) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req collectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := change(req); err != nil {
+		log.Error("Failed to change collection", "name", req.Name, "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeCollections(w)
+}
+
+// writeCollections responds with the collections and their sizes
+func writeCollections(w http.ResponseWriter) {
+	collections, err := search.ListCollections()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(collections)
+}
+
 func HandleStats(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -410,6 +1015,7 @@ func HandleUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	duration := time.Since(start).Round(100 * time.Millisecond)
+	go refreshSavedSearches()
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
@@ -419,6 +1025,17 @@ func HandleUpdate(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// processing is the web UI's processing run, so it can be followed while
+// it runs and only one runs at a time
+var processing struct {
+	sync.Mutex
+	engine  *preprocessing.ProcessingEngine
+	running bool
+}
+
+// HandleProcessSources starts processing the enabled sources in the
+// background. Its progress is served by /process/status and
+// /process/status/stream.
 func HandleProcessSources(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -458,49 +1075,147 @@ func HandleProcessSources(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	processing.Lock()
+	defer processing.Unlock()
+	if processing.running {
+		http.Error(w, "Sources are already being processed", http.StatusConflict)
+		return
+	}
+
 	// Create processing config and engine
 	config := preprocessing.DefaultProcessingConfig()
+	config.CategoryOverrides = s.CategoryOverrides
+	config.ValidateURLs = s.CheckLinks
 	engine := preprocessing.NewProcessingEngine(s.CacheDir, config)
+	processing.engine = engine
+	processing.running = true
 
 	log.Info("Starting source processing from web UI", "sources", len(enabledSources))
-	start := time.Now()
+	go func() {
+		start := time.Now()
+		engine.ProcessAllSources(enabledSources)
+
+		progress := engine.Progress()
+		failed := len(progress.Failed())
+		duration := time.Since(start).Round(100 * time.Millisecond)
+		log.Info("Source processing completed", "processed", len(progress.Sources)-failed, "failed", failed, "duration", duration)
+		refreshSavedSearches()
+
+		processing.Lock()
+		processing.running = false
+		processing.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"sources": len(enabledSources),
+		"message": fmt.Sprintf("Processing %d sources", len(enabledSources)),
+	})
+}
 
-	// Process all enabled sources
-	processed := 0
-	failed := 0
-	for _, source := range enabledSources {
-		log.Info("Processing source", "name", source.Name)
-		err := engine.ProcessSource(source)
+// processingProgress returns the progress of the web UI's processing run.
+// Before any run it reports what has been processed so far. finished is
+// false until the run has started and ended.
+func processingProgress() (progress preprocessing.ProcessingProgress, finished bool, err error) {
+	processing.Lock()
+	engine, running := processing.engine, processing.running
+	processing.Unlock()
+
+	if engine == nil {
+		s, err := settings.LoadSettings()
+		if err != nil {
+			return progress, false, fmt.Errorf("failed to load settings: %w", err)
+		}
+		engine = preprocessing.NewProcessingEngine(s.CacheDir, preprocessing.DefaultProcessingConfig())
+		statuses, err := engine.GetProcessingStatus()
 		if err != nil {
-			log.Error("Failed to process source", "name", source.Name, "error", err)
-			failed++
-		} else {
-			processed++
+			return progress, false, err
 		}
+		return preprocessing.ProcessingProgress{Sources: statuses}, true, nil
 	}
 
-	duration := time.Since(start).Round(100 * time.Millisecond)
-	log.Info("Source processing completed", "processed", processed, "failed", failed, "duration", duration)
+	// The run may not have begun yet when it has only just been started
+	progress = engine.Progress()
+	return progress, !running && !progress.Running && !progress.StartedAt.IsZero(), nil
+}
+
+// HandleProcessStatus returns the progress of processing sources
+func HandleProcessStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	progress, _, err := processingProgress()
+	if err != nil {
+		log.Error("Failed to get processing status", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if failed > 0 {
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"success":   true,
-			"processed": processed,
-			"failed":    failed,
-			"duration":  duration.String(),
-			"message":   fmt.Sprintf("Processing completed with some errors. %d sources processed, %d failed.", processed, failed),
-		})
-	} else {
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"success":   true,
-			"processed": processed,
-			"duration":  duration.String(),
-			"message":   fmt.Sprintf("All %d sources processed successfully", processed),
-		})
+	json.NewEncoder(w).Encode(progress)
+}
+
+// HandleProcessStatusStream streams the progress of processing sources as
+// Server-Sent Events. A "status" event is sent whenever it changes, at most
+// every statusStreamInterval, followed by a "done" event with the final
+// progress once the run has finished.
+func HandleProcessStatusStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(statusStreamInterval)
+	defer ticker.Stop()
+	for {
+		// Take the update channel first so no change is missed
+		var updates <-chan struct{}
+		processing.Lock()
+		if processing.engine != nil {
+			updates = processing.engine.Updates()
+		}
+		processing.Unlock()
+
+		progress, finished, err := processingProgress()
+		if err != nil {
+			log.Error("Failed to get processing status", "error", err)
+			writeEvent(w, flusher, "error", map[string]string{"error": err.Error()})
+			return
+		}
+		if finished {
+			writeEvent(w, flusher, "done", progress)
+			return
+		}
+		writeEvent(w, flusher, "status", progress)
+
+		select {
+		case <-updates:
+		case <-ticker.C: // a run that hasn't begun yet has no updates
+		case <-r.Context().Done():
+			return
+		}
+		select {
+		case <-ticker.C:
+		case <-r.Context().Done():
+			return
+		}
 	}
 }
 
+// statusStreamInterval is how often processing progress is streamed at most
+const statusStreamInterval = 250 * time.Millisecond
+
 func HandleSettings(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
@@ -521,7 +1236,7 @@ func HandleSettings(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if r.Method == "POST" {
-		var s settings.Settings
+		s := settings.DefaultSettings()
 		if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
 			log.Error("Failed to decode settings from request", "error", err)
 			http.Error(w, fmt.Errorf("failed to decode settings: %w", err).Error(), http.StatusBadRequest)
@@ -906,12 +1621,22 @@ func HandleVirusTotalScan(w http.ResponseWriter, r *http.Request) {
 
 // Define a SearchResult struct for JSON encoding
 type SearchResult struct {
-	Category    string `json:"category"`
-	Description string `json:"description"`
-	URL         string `json:"url"`
-	Name        string `json:"name"`
-	Score       int    `json:"score"`
-	Source      string `json:"source"`
+	Category    string              `json:"category"`
+	Description string              `json:"description"`
+	URL         string              `json:"url"`
+	Name        string              `json:"name"`
+	Score       int                 `json:"score"`
+	Source      string              `json:"source"`
+	SourceType  string              `json:"source_type"`
+	Tags        []string            `json:"tags"`
+	Collections []string            `json:"collections,omitempty"`
+	Appearances []search.Appearance `json:"appearances"`
+	AppearsIn   int                 `json:"appears_in"`
+	FilePath    string              `json:"file_path,omitempty"`
+	LineNumber  int                 `json:"line_number,omitempty"`
+	SourceURL   string              `json:"source_url,omitempty"`
+	LinkStatus  int                 `json:"link_status,omitempty"`
+	Dead        bool                `json:"dead,omitempty"`
 }
 
 // HandleLibrary handles the library page